@@ -0,0 +1,93 @@
+package NeuralNetwork
+
+import (
+	"runtime"
+	"sync"
+)
+
+// the smallest number of inputs given to a single worker by the batched inference
+const minBatchPerWorker = 64
+
+// Returns values of all output nodes for every input in the inputs' order. All inputs are validated
+// before any of them is calculated. Big batches are split between runtime.NumCPU() workers
+func (neuralNet *Network) PredictBatch(inputs [][]float64) ([][]float64, error) {
+	if err := neuralNet.validateBatchInputData(inputs); err != nil {
+		return nil, err
+	}
+	return neuralNet.getOutputsBatch(inputs), nil
+}
+
+// Returns the best output label for every input in the inputs' order. All inputs are validated
+// before any of them is calculated. Big batches are split between runtime.NumCPU() workers
+func (neuralNet *Network) ClassifyBatch(inputs [][]float64) ([]string, error) {
+	if err := neuralNet.validateTask(classification, multiLabel); err != nil {
+		return nil, err
+	}
+	if err := neuralNet.validateBatchInputData(inputs); err != nil {
+		return nil, err
+	}
+
+	outputLabels := neuralNet.network.GetOutputLabels()
+	labels := make([]string, len(inputs))
+	for i, outputs := range neuralNet.getOutputsBatch(inputs) {
+		bestIndex := 0
+		for j, output := range outputs {
+			if output > outputs[bestIndex] {
+				bestIndex = j
+			}
+		}
+		labels[i] = outputLabels[bestIndex]
+	}
+	return labels, nil
+}
+
+// calculates concurrently outputs of the network or the averaged outputs of the ensemble for all inputs
+func (neuralNet *Network) getOutputsBatch(inputs [][]float64) [][]float64 {
+	numberOfWorkers := runtime.NumCPU()
+	if maxWorkers := (len(inputs) + minBatchPerWorker - 1) / minBatchPerWorker; maxWorkers < numberOfWorkers {
+		numberOfWorkers = maxWorkers
+	}
+	if numberOfWorkers <= 1 {
+		return neuralNet.getOutputsChunk(inputs)
+	}
+
+	outputs := make([][]float64, len(inputs))
+	chunkSize := (len(inputs) + numberOfWorkers - 1) / numberOfWorkers
+	var wg sync.WaitGroup
+	for start := 0; start < len(inputs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			copy(outputs[start:end], neuralNet.getOutputsChunk(inputs[start:end]))
+		}(start, end)
+	}
+	wg.Wait()
+	return outputs
+}
+
+// returns outputs of the network or the averaged outputs of the ensemble for the inputs
+func (neuralNet *Network) getOutputsChunk(inputs [][]float64) [][]float64 {
+	if len(neuralNet.ensemble) <= 1 {
+		return neuralNet.network.GetOutputsBatch(inputs)
+	}
+
+	// the first network's outputs are reused for the averaged ones
+	outputs := neuralNet.ensemble[0].GetOutputsBatch(inputs)
+	for j := range outputs {
+		for k := range outputs[j] {
+			outputs[j][k] /= float64(len(neuralNet.ensemble))
+		}
+	}
+	for i := 1; i < len(neuralNet.ensemble); i++ {
+		for j, memberOutputs := range neuralNet.ensemble[i].GetOutputsBatch(inputs) {
+			for k, output := range memberOutputs {
+				outputs[j][k] += output / float64(len(neuralNet.ensemble))
+			}
+		}
+	}
+	return outputs
+}
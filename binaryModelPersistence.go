@@ -0,0 +1,170 @@
+package NeuralNetwork
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// the first bytes of every binary model
+var binaryModelMagic = [4]byte{'G', 'O', 'N', 'N'}
+
+// the version of the binary model's format. It has to be increased with every incompatible change
+const binaryModelFormatVersion = 2
+
+// Precisions in which weights and biases of binary models can be saved
+type BinaryPrecision = network.FloatPrecision
+
+const (
+	Float64Precision = network.Float64Precision
+	Float32Precision = network.Float32Precision
+)
+
+// Errors returned by LoadBinary. They can be checked with errors.Is
+var (
+	ErrNotBinaryModel          = errors.New("the data isn't a binary model")
+	ErrUnsupportedModelVersion = errors.New("unsupported binary model version")
+	ErrTruncatedModel          = errors.New("the binary model is truncated")
+	ErrCorruptedModel          = errors.New("the binary model is corrupted")
+)
+
+// the part of the binary model written before the network
+type binaryModelHeader struct {
+	Magic                    [4]byte
+	Version                  uint16
+	Task                     uint8
+	Precision                uint8
+	NumberOfTrainingNetworks uint32
+	PayloadLength            uint32 // the number of bytes of the network
+}
+
+// Writes the network in a compact binary format. All numbers are little-endian:
+//
+//	[4]byte   magic "GONN"
+//	uint16    format version (2)
+//	uint8     task: 0 - classification, 1 - regression, 2 - multi-label classification
+//	uint8     precision of weights and biases: 0 - float64, 1 - float32
+//	uint32    number of training networks
+//	uint32    payload length - the number of bytes of the network
+//	...       the network written by network.EncodeBinary
+//	uint32    CRC32 (IEEE) of all previous bytes
+//
+// Training data and trainer's state aren't saved
+func (neuralNet *Network) SaveBinary(w io.Writer, precision BinaryPrecision) error {
+	if precision != Float64Precision && precision != Float32Precision {
+		return errors.New("unknown precision")
+	}
+
+	// the network is encoded first as its length is written before it
+	var payload bytes.Buffer
+	if err := neuralNet.network.EncodeBinary(&payload, precision); err != nil {
+		return err
+	}
+	header := binaryModelHeader{
+		Magic:                    binaryModelMagic,
+		Version:                  binaryModelFormatVersion,
+		Task:                     uint8(neuralNet.task),
+		Precision:                uint8(precision),
+		NumberOfTrainingNetworks: uint32(neuralNet.numberOfTrainingNetworks),
+		PayloadLength:            uint32(payload.Len()),
+	}
+
+	bufferedWriter := bufio.NewWriter(w)
+	if err := binary.Write(bufferedWriter, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := bufferedWriter.Write(payload.Bytes()); err != nil {
+		return err
+	}
+	if err := binary.Write(bufferedWriter, binary.LittleEndian, binaryModelChecksum(header, payload.Bytes())); err != nil {
+		return err
+	}
+	return bufferedWriter.Flush()
+}
+
+// Returns the network read from the binary format written by SaveBinary.
+// Returned errors wrap ErrNotBinaryModel, ErrUnsupportedModelVersion, ErrTruncatedModel or ErrCorruptedModel
+func LoadBinary(r io.Reader) (*Network, error) {
+	bufferedReader := bufio.NewReader(r)
+	var header binaryModelHeader
+	if err := binary.Read(bufferedReader, binary.LittleEndian, &header); err != nil {
+		return nil, binaryReadError(err)
+	}
+	if header.Magic != binaryModelMagic {
+		return nil, ErrNotBinaryModel
+	}
+	if header.Version != binaryModelFormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedModelVersion, header.Version)
+	}
+
+	// the whole payload is checked before it is decoded, so every changed byte is reported as the corruption
+	var body bytes.Buffer
+	if _, err := io.CopyN(&body, bufferedReader, int64(header.PayloadLength)+4); err != nil {
+		if !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptedModel, err)
+		}
+		// the model is whole, but its length is changed, if it matches its checksum with the length of the read data
+		if body.Len() >= 4 {
+			header.PayloadLength = uint32(body.Len() - 4)
+			if hasValidChecksum(header, body.Bytes()) {
+				return nil, fmt.Errorf("%w: wrong payload length", ErrCorruptedModel)
+			}
+		}
+		return nil, ErrTruncatedModel
+	}
+	if !hasValidChecksum(header, body.Bytes()) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptedModel)
+	}
+
+	precision := BinaryPrecision(header.Precision)
+	if precision != Float64Precision && precision != Float32Precision {
+		return nil, fmt.Errorf("%w: unknown precision", ErrCorruptedModel)
+	}
+	payload := bytes.NewReader(body.Bytes()[:header.PayloadLength])
+	net, err := network.DecodeBinary(payload, precision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptedModel, err)
+	}
+	if payload.Len() != 0 {
+		return nil, fmt.Errorf("%w: unread bytes after the network", ErrCorruptedModel)
+	}
+
+	myTask := task(header.Task)
+	if _, ok := taskNames[myTask]; !ok {
+		return nil, fmt.Errorf("%w: unknown task", ErrCorruptedModel)
+	}
+	neuralNet, err := newLoadedNeuralNetwork(myTask.String(), int(header.NumberOfTrainingNetworks), net)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptedModel, err)
+	}
+	return neuralNet, nil
+}
+
+// returns the CRC32 of the header and the payload
+func binaryModelChecksum(header binaryModelHeader, payload []byte) uint32 {
+	checksum := crc32.NewIEEE()
+	// writing to the hash never fails
+	binary.Write(checksum, binary.LittleEndian, header)
+	checksum.Write(payload)
+	return checksum.Sum32()
+}
+
+// returns whether the body - the payload followed by its checksum - matches the checksum
+func hasValidChecksum(header binaryModelHeader, body []byte) bool {
+	payloadLength := len(body) - 4
+	return binary.LittleEndian.Uint32(body[payloadLength:]) == binaryModelChecksum(header, body[:payloadLength])
+}
+
+// wraps the error of reading the binary model with the matching typed error
+func binaryReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncatedModel
+	}
+	return fmt.Errorf("%w: %v", ErrCorruptedModel, err)
+}
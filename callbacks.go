@@ -0,0 +1,31 @@
+package NeuralNetwork
+
+import (
+	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
+)
+
+// Adds the callback notified about every generation, epoch and the end of every next training.
+// A callback can stop the training by returning training.ErrStopTraining
+func (neuralNet *Network) AddCallback(callback training.Callback) {
+	neuralNet.callbacks = append(neuralNet.callbacks, callback)
+	if neuralNet.trainer.Initialized {
+		neuralNet.trainer.AddCallback(callback)
+	}
+}
+
+// Sets the channel to which all training events are sent. The training waits until every event is received,
+// so the channel has to be read or buffered. It isn't closed after the training. Nil turns the events off
+func (neuralNet *Network) SetEventChannel(events chan<- training.TrainingEvent) {
+	neuralNet.events = events
+	if neuralNet.trainer.Initialized {
+		neuralNet.trainer.SetEventChannel(events)
+	}
+}
+
+// gives the trainer the network's callbacks and event channel
+func (neuralNet *Network) setListeners(trainer *training.Trainer) {
+	for _, callback := range neuralNet.callbacks {
+		trainer.AddCallback(callback)
+	}
+	trainer.SetEventChannel(neuralNet.events)
+}
@@ -0,0 +1,181 @@
+package NeuralNetwork
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
+)
+
+// Config describes the network and its training so they can be changed without recompiling.
+// Only layers and number of training networks are required, the rest have the same defaults as the constructors
+type Config struct {
+	// classification, regression or multi-label classification. Classification is the default one
+	Task         string       `json:"task,omitempty"`
+	Layers       []int        `json:"layers"`
+	OutputLabels []string     `json:"outputLabels,omitempty"`
+	Activations  []Activation `json:"activations,omitempty"`
+	// given for every layer except the input one like activations
+	Initializers             []InitializerConfig `json:"initializers,omitempty"`
+	NumberOfTrainingNetworks int                 `json:"numberOfTrainingNetworks"`
+	EnsembleSize             int                 `json:"ensembleSize,omitempty"`
+	// makes the initial weights and the training reproducible
+	Seed *int64 `json:"seed,omitempty"`
+	// the algorithm used by Train
+	Algorithm training.Algorithm        `json:"algorithm,omitempty"`
+	Loss      *training.LossConfig      `json:"loss,omitempty"`
+	Optimizer *training.OptimizerConfig `json:"optimizer,omitempty"`
+	Evolution *training.EvolutionConfig `json:"evolution,omitempty"`
+	// how the evolution chooses parents, if it isn't given the evolution config decides
+	Selection *training.SelectionConfig `json:"selection,omitempty"`
+	// how the evolution mixes parents' genes, if it isn't given genes are taken from both parents with the same chance
+	Crossover *training.CrossoverConfig `json:"crossover,omitempty"`
+	// how the evolution mutates children, if it isn't given the evolution config decides
+	Mutation *training.MutationConfig `json:"mutation,omitempty"`
+	Batch    *training.BatchConfig    `json:"batch,omitempty"`
+	// the fraction of the training data used for the validation
+	ValidationSplit float64                       `json:"validationSplit,omitempty"`
+	EarlyStopping   *training.EarlyStoppingConfig `json:"earlyStopping,omitempty"`
+}
+
+// Returns the configuration read from JSON. Unknown fields are treated as an error
+// so a misspelled setting doesn't get silently ignored
+func LoadConfig(r io.Reader) (Config, error) {
+	var config Config
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, err
+	}
+	if decoder.More() {
+		return Config{}, errors.New("the configuration has more than one JSON value")
+	}
+	return config, nil
+}
+
+// Returns an initialized neural network described by the JSON configuration file
+func NewNeuralNetworkFromConfig(path string) (*Network, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config, err := LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewNeuralNetworkWithConfig(config)
+}
+
+// Returns an initialized neural network described by the configuration
+func NewNeuralNetworkWithConfig(config Config) (*Network, error) {
+	myTask := classification
+	if config.Task != "" {
+		var err error
+		if myTask, err = parseTask(config.Task); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := []Option{
+		withTask(myTask),
+		WithPopulationSize(config.NumberOfTrainingNetworks),
+		WithLayers(config.Layers...),
+		WithLabels(config.OutputLabels...),
+		WithActivation(config.Activations...),
+	}
+	if len(config.Initializers) != 0 {
+		initializers := make([]Initializer, len(config.Initializers))
+		for i, initializerConfig := range config.Initializers {
+			var err error
+			if initializers[i], err = initializerConfig.NewInitializer(); err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, WithInitializer(initializers...))
+	}
+	if config.Seed != nil {
+		opts = append(opts, WithSeed(*config.Seed))
+	}
+	neuralNet, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.EnsembleSize != 0 {
+		if err := neuralNet.SetEnsembleSize(config.EnsembleSize); err != nil {
+			return nil, err
+		}
+	}
+	if config.Algorithm != EvolutionTraining && config.Algorithm != BackPropagationTraining {
+		return nil, errors.New("unknown training algorithm")
+	}
+	neuralNet.algorithm = config.Algorithm
+	if config.Loss != nil {
+		loss, err := config.Loss.NewLoss()
+		if err != nil {
+			return nil, err
+		}
+		if err := neuralNet.SetLoss(loss); err != nil {
+			return nil, err
+		}
+	}
+	if config.Optimizer != nil {
+		optimizer, err := config.Optimizer.NewOptimizer()
+		if err != nil {
+			return nil, err
+		}
+		if err := neuralNet.SetOptimizer(optimizer); err != nil {
+			return nil, err
+		}
+	}
+	if config.Evolution != nil {
+		if err := neuralNet.SetEvolutionConfig(*config.Evolution); err != nil {
+			return nil, err
+		}
+	}
+	if config.Selection != nil {
+		selector, err := config.Selection.NewSelector()
+		if err != nil {
+			return nil, err
+		}
+		if err := neuralNet.SetSelector(selector); err != nil {
+			return nil, err
+		}
+	}
+	if config.Crossover != nil {
+		crossover, err := config.Crossover.NewCrossover()
+		if err != nil {
+			return nil, err
+		}
+		if err := neuralNet.SetCrossover(crossover); err != nil {
+			return nil, err
+		}
+	}
+	if config.Mutation != nil {
+		mutator, err := config.Mutation.NewMutator()
+		if err != nil {
+			return nil, err
+		}
+		if err := neuralNet.SetMutator(mutator); err != nil {
+			return nil, err
+		}
+	}
+	if config.Batch != nil {
+		if err := neuralNet.SetBatchConfig(*config.Batch); err != nil {
+			return nil, err
+		}
+	}
+	if err := neuralNet.SetValidationSplit(config.ValidationSplit); err != nil {
+		return nil, err
+	}
+	if config.EarlyStopping != nil {
+		if err := neuralNet.SetEarlyStopping(*config.EarlyStopping); err != nil {
+			return nil, err
+		}
+	}
+	return neuralNet, nil
+}
@@ -0,0 +1,18 @@
+package NeuralNetwork
+
+import (
+	"github.com/Basileus1990/NeuralNetwork.git/integral/metrics"
+)
+
+// Returns the accuracy, per-class precision, recall and F1, their averages, the confusion matrix,
+// top-k accuracies and the log loss of the network (or the ensemble) for the given data.
+// The report's String method formats it as a text table
+func (neuralNet *Network) Evaluate(inputs [][]float64, labels []string) (metrics.Report, error) {
+	if err := neuralNet.validateTask(classification); err != nil {
+		return metrics.Report{}, err
+	}
+	if err := neuralNet.validateTrainingInputData(inputs, labels); err != nil {
+		return metrics.Report{}, err
+	}
+	return metrics.Evaluate(neuralNet.network.GetOutputLabels(), neuralNet.getOutputsBatch(inputs), labels)
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// the smallest probability used by the log loss, so a wrong prediction with the probability of 0 doesn't give infinity
+const minProbability = 1e-15
+
+// Precision, recall and F1 score of a single class or their average
+type Scores struct {
+	// the fraction of the class' predictions which were correct
+	Precision float64
+	// the fraction of the class' data sets which were predicted correctly
+	Recall float64
+	// the harmonic mean of the precision and the recall
+	F1 float64
+}
+
+// The scores of a single class and the number of its data sets
+type ClassScores struct {
+	Scores
+	Support int
+}
+
+// The quality of a classification measured on the given data sets
+type Report struct {
+	// the classes in the order of the network's output labels, repeated labels appear once
+	Labels []string
+	// the fraction of data sets which best output node has the expected label
+	Accuracy float64
+	Classes  map[string]ClassScores
+	// the unweighted mean of all classes' scores
+	MacroAverage Scores
+	// the scores calculated from the summed true positives, false positives and false negatives of all classes
+	MicroAverage Scores
+	// the number of data sets for every expected label and predicted label - ConfusionMatrix[expected][predicted]
+	ConfusionMatrix map[string]map[string]int
+	// TopKAccuracy[k-1] is the fraction of data sets which expected label is among the k best output nodes' labels
+	TopKAccuracy []float64
+	// the average negative logarithm of the expected label's probability. Outputs are divided by their sum
+	// to be the probabilities, so they don't have to come from the softmax
+	LogLoss float64
+	// the number of data sets
+	Support int
+}
+
+// Returns the report for the outputs of the network which output nodes have the given labels.
+// The expected labels have to be given for every output
+func Evaluate(outputLabels []string, outputs [][]float64, expectedLabels []string) (Report, error) {
+	if len(outputs) == 0 {
+		return Report{}, errors.New("there is no data to evaluate")
+	}
+	if len(outputs) != len(expectedLabels) {
+		return Report{}, errors.New("number of outputs is not the same as number of expected labels")
+	}
+
+	report := newReport(outputLabels)
+	for i := range outputs {
+		if len(outputs[i]) != len(outputLabels) {
+			return Report{}, errors.New("number of outputs has to be the same as number of output labels")
+		}
+		if _, ok := report.Classes[expectedLabels[i]]; !ok {
+			return Report{}, errors.New("given label doesn't exist: " + expectedLabels[i])
+		}
+		report.add(outputLabels, outputs[i], expectedLabels[i])
+	}
+	report.calculateScores()
+	return report, nil
+}
+
+// returns the empty report with all classes
+func newReport(outputLabels []string) Report {
+	report := Report{
+		Classes:         make(map[string]ClassScores),
+		ConfusionMatrix: make(map[string]map[string]int),
+	}
+	for _, label := range outputLabels {
+		if _, ok := report.Classes[label]; ok {
+			continue
+		}
+		report.Labels = append(report.Labels, label)
+		report.Classes[label] = ClassScores{}
+		report.ConfusionMatrix[label] = make(map[string]int)
+	}
+	report.TopKAccuracy = make([]float64, len(report.Labels))
+	return report
+}
+
+// adds a single data set to the confusion matrix, top-k accuracy and log loss.
+// They are summed up and turned into fractions by calculateScores
+func (report *Report) add(outputLabels []string, outputs []float64, expectedLabel string) {
+	report.Support++
+
+	// output nodes from the best one, nodes with the same label count as one class
+	order := make([]int, len(outputs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return outputs[order[i]] > outputs[order[j]]
+	})
+	report.ConfusionMatrix[expectedLabel][outputLabels[order[0]]]++
+	rank := 0
+	seenLabels := make(map[string]bool)
+	for _, index := range order {
+		label := outputLabels[index]
+		if seenLabels[label] {
+			continue
+		}
+		if label == expectedLabel {
+			break
+		}
+		seenLabels[label] = true
+		rank++
+	}
+	for k := rank; k < len(report.TopKAccuracy); k++ {
+		report.TopKAccuracy[k]++
+	}
+
+	sum, expected := 0.0, 0.0
+	for i, output := range outputs {
+		output = math.Max(output, 0)
+		sum += output
+		if outputLabels[i] == expectedLabel {
+			expected += output
+		}
+	}
+	probability := minProbability
+	if sum > 0 {
+		probability = math.Max(expected/sum, minProbability)
+	}
+	report.LogLoss -= math.Log(probability)
+}
+
+// turns the summed up values into averages and calculates all scores from the confusion matrix
+func (report *Report) calculateScores() {
+	support := float64(report.Support)
+	for k := range report.TopKAccuracy {
+		report.TopKAccuracy[k] /= support
+	}
+	report.LogLoss /= support
+
+	var allTruePositives, allFalsePositives, allFalseNegatives int
+	for _, label := range report.Labels {
+		truePositives := report.ConfusionMatrix[label][label]
+		falsePositives, falseNegatives, classSupport := 0, 0, 0
+		for _, other := range report.Labels {
+			classSupport += report.ConfusionMatrix[label][other]
+			if other != label {
+				falsePositives += report.ConfusionMatrix[other][label]
+				falseNegatives += report.ConfusionMatrix[label][other]
+			}
+		}
+		allTruePositives += truePositives
+		allFalsePositives += falsePositives
+		allFalseNegatives += falseNegatives
+
+		scores := newScores(truePositives, falsePositives, falseNegatives)
+		report.Classes[label] = ClassScores{Scores: scores, Support: classSupport}
+		report.MacroAverage.Precision += scores.Precision / float64(len(report.Labels))
+		report.MacroAverage.Recall += scores.Recall / float64(len(report.Labels))
+		report.MacroAverage.F1 += scores.F1 / float64(len(report.Labels))
+	}
+	report.Accuracy = float64(allTruePositives) / support
+	report.MicroAverage = newScores(allTruePositives, allFalsePositives, allFalseNegatives)
+}
+
+// returns scores of a class. Scores which can't be calculated because there were no predictions
+// or no data sets of the class are 0
+func newScores(truePositives, falsePositives, falseNegatives int) Scores {
+	var scores Scores
+	if truePositives+falsePositives != 0 {
+		scores.Precision = float64(truePositives) / float64(truePositives+falsePositives)
+	}
+	if truePositives+falseNegatives != 0 {
+		scores.Recall = float64(truePositives) / float64(truePositives+falseNegatives)
+	}
+	if scores.Precision+scores.Recall != 0 {
+		scores.F1 = 2 * scores.Precision * scores.Recall / (scores.Precision + scores.Recall)
+	}
+	return scores
+}
+
+// Returns the fraction of data sets which expected label is among the k best output nodes' labels.
+// k bigger than the number of classes gives 1
+func (report Report) TopK(k int) float64 {
+	if k <= 0 || len(report.TopKAccuracy) == 0 {
+		return 0
+	}
+	if k > len(report.TopKAccuracy) {
+		k = len(report.TopKAccuracy)
+	}
+	return report.TopKAccuracy[k-1]
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	labels := []string{"cat", "dog", "bird"}
+	outputs := [][]float64{
+		{0.7, 0.2, 0.1}, // cat -> cat
+		{0.6, 0.3, 0.1}, // cat -> cat
+		{0.3, 0.5, 0.2}, // cat -> dog, the second best
+		{0.1, 0.8, 0.1}, // dog -> dog
+		{0.5, 0.4, 0.1}, // dog -> cat, the second best
+		{0.2, 0.5, 0.3}, // bird -> dog, the second best
+	}
+	expected := []string{"cat", "cat", "cat", "dog", "dog", "bird"}
+	report, err := Evaluate(labels, outputs, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Accuracy != 0.5 || report.Support != 6 {
+		t.Fatal("wrong accuracy: ", report.Accuracy)
+	}
+	if report.ConfusionMatrix["cat"]["cat"] != 2 || report.ConfusionMatrix["cat"]["dog"] != 1 ||
+		report.ConfusionMatrix["dog"]["cat"] != 1 || report.ConfusionMatrix["bird"]["dog"] != 1 || report.ConfusionMatrix["bird"]["bird"] != 0 {
+		t.Fatal("wrong confusion matrix: ", report.ConfusionMatrix)
+	}
+
+	checkScores := func(name string, scores Scores, precision, recall float64) {
+		f1 := 0.0
+		if precision+recall != 0 {
+			f1 = 2 * precision * recall / (precision + recall)
+		}
+		if math.Abs(scores.Precision-precision) > 1e-12 || math.Abs(scores.Recall-recall) > 1e-12 || math.Abs(scores.F1-f1) > 1e-12 {
+			t.Fatal("wrong scores of ", name, ": ", scores)
+		}
+	}
+	checkScores("cat", report.Classes["cat"].Scores, 2.0/3, 2.0/3)
+	checkScores("dog", report.Classes["dog"].Scores, 1.0/3, 1.0/2)
+	checkScores("bird", report.Classes["bird"].Scores, 0, 0)
+	if report.Classes["cat"].Support != 3 || report.Classes["bird"].Support != 1 {
+		t.Fatal("wrong supports: ", report.Classes)
+	}
+	macroPrecision, macroRecall := (2.0/3+1.0/3)/3, (2.0/3+1.0/2)/3
+	if math.Abs(report.MacroAverage.Precision-macroPrecision) > 1e-12 || math.Abs(report.MacroAverage.Recall-macroRecall) > 1e-12 {
+		t.Fatal("wrong macro average: ", report.MacroAverage)
+	}
+	checkScores("micro average", report.MicroAverage, 0.5, 0.5)
+
+	if report.TopK(1) != 0.5 || report.TopK(2) != 1 || report.TopK(3) != 1 || report.TopK(10) != 1 || report.TopK(0) != 0 {
+		t.Fatal("wrong top-k accuracy: ", report.TopKAccuracy)
+	}
+	logLoss := -(math.Log(0.7) + math.Log(0.6) + math.Log(0.3) + math.Log(0.8) + math.Log(0.4) + math.Log(0.3)) / 6
+	if math.Abs(report.LogLoss-logLoss) > 1e-12 {
+		t.Fatal("wrong log loss: ", report.LogLoss, logLoss)
+	}
+
+	text := report.String()
+	for _, part := range []string{"precision", "macro avg", "micro avg", "top-2 accuracy: 1.0000", "log loss:", "confusion matrix"} {
+		if !strings.Contains(text, part) {
+			t.Fatal("the report doesn't contain ", part, ":\n", text)
+		}
+	}
+}
+
+func TestEvaluateRepeatedLabels(t *testing.T) {
+	// nodes with the same label are a single class
+	report, err := Evaluate([]string{"a", "b", "a"}, [][]float64{{0.2, 0.3, 0.5}, {0, 0, 0}}, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Labels) != 2 || len(report.TopKAccuracy) != 2 || report.Accuracy != 0.5 {
+		t.Fatal("repeated labels haven't been merged: ", report.Labels, report.Accuracy)
+	}
+	// all outputs of 0 give the smallest probability instead of infinity
+	if math.IsInf(report.LogLoss, 0) || math.Abs(report.LogLoss-(-math.Log(0.7)-math.Log(minProbability))/2) > 1e-9 {
+		t.Fatal("wrong log loss: ", report.LogLoss)
+	}
+}
+
+func TestEvaluateBadData(t *testing.T) {
+	labels := []string{"a", "b"}
+	if _, err := Evaluate(labels, nil, nil); err == nil {
+		t.Fatal("no data got through")
+	}
+	if _, err := Evaluate(labels, [][]float64{{1, 0}}, []string{"a", "b"}); err == nil {
+		t.Fatal("different number of outputs and labels got through")
+	}
+	if _, err := Evaluate(labels, [][]float64{{1, 0, 0}}, []string{"a"}); err == nil {
+		t.Fatal("wrong number of outputs got through")
+	}
+	if _, err := Evaluate(labels, [][]float64{{1, 0}}, []string{"c"}); err == nil {
+		t.Fatal("unknown label got through")
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Returns the report as a text table of every class' scores and averages
+// followed by top-k accuracies, the log loss and the confusion matrix
+func (report Report) String() string {
+	var builder strings.Builder
+	w := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(w, "\tprecision\trecall\tf1-score\tsupport\t")
+	for _, label := range report.Labels {
+		class := report.Classes[label]
+		fmt.Fprintf(w, "%s\t%.4f\t%.4f\t%.4f\t%d\t\n", label, class.Precision, class.Recall, class.F1, class.Support)
+	}
+	fmt.Fprintln(w, "\t\t\t\t\t")
+	fmt.Fprintf(w, "accuracy\t\t\t%.4f\t%d\t\n", report.Accuracy, report.Support)
+	for _, average := range []struct {
+		name   string
+		scores Scores
+	}{{"macro avg", report.MacroAverage}, {"micro avg", report.MicroAverage}} {
+		fmt.Fprintf(w, "%s\t%.4f\t%.4f\t%.4f\t%d\t\n", average.name, average.scores.Precision, average.scores.Recall, average.scores.F1, report.Support)
+	}
+	w.Flush()
+
+	builder.WriteString("\n")
+	for k, accuracy := range report.TopKAccuracy {
+		fmt.Fprintf(&builder, "top-%d accuracy: %.4f\n", k+1, accuracy)
+	}
+	fmt.Fprintf(&builder, "log loss: %.4f\n", report.LogLoss)
+
+	builder.WriteString("\nconfusion matrix (rows - expected, columns - predicted):\n")
+	w = tabwriter.NewWriter(&builder, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "\t%s\t\n", strings.Join(report.Labels, "\t"))
+	for _, expected := range report.Labels {
+		fmt.Fprintf(w, "%s\t", expected)
+		for _, predicted := range report.Labels {
+			fmt.Fprintf(w, "%d\t", report.ConfusionMatrix[expected][predicted])
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+	return builder.String()
+}
@@ -0,0 +1,193 @@
+package network
+
+import (
+	"errors"
+	"math"
+)
+
+// Activation determines which function is used on the layer's nodes' values
+type Activation int
+
+const (
+	Sigmoid Activation = iota
+	Tanh
+	ReLU
+	LeakyReLU
+	ELU
+	GELU
+	Softplus
+	Linear
+	// turns the layer's values into probabilities which sum up to 1
+	Softmax
+)
+
+// the slope of the leaky ReLU for negative values
+const leakyReLUSlope = 0.01
+
+// the value to which the ELU goes for negative values (with a minus)
+const eluAlpha = 1.0
+
+var activationNames = map[Activation]string{
+	Sigmoid:   "sigmoid",
+	Tanh:      "tanh",
+	ReLU:      "relu",
+	LeakyReLU: "leakyRelu",
+	ELU:       "elu",
+	GELU:      "gelu",
+	Softplus:  "softplus",
+	Linear:    "linear",
+	Softmax:   "softmax",
+}
+
+func (activation Activation) String() string {
+	if name, ok := activationNames[activation]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// returns whether the activation is one of the defined ones
+func (activation Activation) IsValid() bool {
+	_, ok := activationNames[activation]
+	return ok
+}
+
+// writes activated values of all given values of a layer to the outputs
+func (activation Activation) activate(inputs, outputs []float64) {
+	if activation == Softmax {
+		maxInput := math.Inf(-1)
+		for _, input := range inputs {
+			maxInput = math.Max(maxInput, input)
+		}
+		// the max value is subtracted so the exponent can't overflow
+		sum := 0.0
+		for i, input := range inputs {
+			outputs[i] = math.Exp(input - maxInput)
+			sum += outputs[i]
+		}
+		for i := range outputs {
+			outputs[i] /= sum
+		}
+		return
+	}
+
+	for i, input := range inputs {
+		outputs[i] = activation.activateValue(input)
+	}
+}
+
+// returns the activated value of a single node
+func (activation Activation) activateValue(input float64) float64 {
+	switch activation {
+	case Tanh:
+		return math.Tanh(input)
+	case ReLU:
+		return math.Max(input, 0)
+	case LeakyReLU:
+		if input < 0 {
+			return leakyReLUSlope * input
+		}
+		return input
+	case ELU:
+		if input < 0 {
+			return eluAlpha * (math.Exp(input) - 1)
+		}
+		return input
+	case GELU:
+		return input * standardNormalCDF(input)
+	case Softplus:
+		// written this way so the exponent can't overflow
+		return math.Max(input, 0) + math.Log1p(math.Exp(-math.Abs(input)))
+	case Linear:
+		return input
+	default:
+		return sigmoid(input)
+	}
+}
+
+// Returns derivatives of the cost with respect to the layer's values before the activation.
+// It takes the values before and after the activation and the derivatives with respect to the activated values
+func (activation Activation) backward(inputs, outputs, outputGradients []float64) []float64 {
+	inputGradients := make([]float64, len(inputs))
+	if activation == Softmax {
+		// every softmax output depends on every input
+		weightedSum := 0.0
+		for i := range outputs {
+			weightedSum += outputGradients[i] * outputs[i]
+		}
+		for i := range outputs {
+			inputGradients[i] = outputs[i] * (outputGradients[i] - weightedSum)
+		}
+		return inputGradients
+	}
+
+	for i := range inputs {
+		inputGradients[i] = outputGradients[i] * activation.derivative(inputs[i], outputs[i])
+	}
+	return inputGradients
+}
+
+// returns the derivative of the activation function for a single node
+func (activation Activation) derivative(input, output float64) float64 {
+	switch activation {
+	case Tanh:
+		return 1 - output*output
+	case ReLU:
+		if input > 0 {
+			return 1
+		}
+		return 0
+	case LeakyReLU:
+		if input < 0 {
+			return leakyReLUSlope
+		}
+		return 1
+	case ELU:
+		if input < 0 {
+			return output + eluAlpha
+		}
+		return 1
+	case GELU:
+		return standardNormalCDF(input) + input*math.Exp(-input*input/2)/math.Sqrt(2*math.Pi)
+	case Softplus:
+		return sigmoid(input)
+	case Linear:
+		return 1
+	default:
+		return output * (1 - output)
+	}
+}
+
+func sigmoid(value float64) float64 {
+	return 1.0 / (1 + math.Exp(-value))
+}
+
+func standardNormalCDF(value float64) float64 {
+	return 0.5 * (1 + math.Erf(value/math.Sqrt2))
+}
+
+// returns the activation with the given name
+func ParseActivation(name string) (Activation, error) {
+	for activation, activationName := range activationNames {
+		if activationName == name {
+			return activation, nil
+		}
+	}
+	return 0, errors.New("unknown activation function: " + name)
+}
+
+func (activation Activation) MarshalText() ([]byte, error) {
+	if !activation.IsValid() {
+		return nil, errors.New("unknown activation function")
+	}
+	return []byte(activation.String()), nil
+}
+
+func (activation *Activation) UnmarshalText(text []byte) error {
+	parsed, err := ParseActivation(string(text))
+	if err != nil {
+		return err
+	}
+	*activation = parsed
+	return nil
+}
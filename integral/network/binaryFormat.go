@@ -0,0 +1,249 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// FloatPrecision determines how weights and biases are stored in the binary format
+type FloatPrecision uint8
+
+const (
+	Float64Precision FloatPrecision = iota
+	// takes half of the space but the parameters lose their precision
+	Float32Precision
+)
+
+// limits protecting from allocating huge amounts of memory for a corrupted data
+const (
+	maxBinaryLayers      = 1 << 16
+	maxBinaryNodes       = 1 << 24
+	maxBinaryLabelLength = 1 << 20
+)
+
+// the amount of parameters read at once so a truncated data is noticed before allocating all of them
+const binaryReadChunk = 4096
+
+var byteOrder = binary.LittleEndian
+
+// Writes the network in the binary format. All numbers are little-endian:
+//
+//	uint32                  number of layers
+//	uint32 * layers         number of nodes per layer
+//	uint8 * (layers-1)      activations of all layers except the input one
+//	uint8                   1 if the network has output labels, otherwise 0
+//	(uint32 + bytes) * outputs   length and bytes of every output label, only if the network has them
+//	float * parameters      for every layer except the input one its biases and then its weights,
+//	                        weights of every node from all previous layer's nodes one after another
+//
+// Floats are float64 or float32 depending on the precision
+func (net *Network) EncodeBinary(w io.Writer, precision FloatPrecision) error {
+	nodesPerLayer := net.GetNetworkStructure()
+	header := []uint32{uint32(len(nodesPerLayer))}
+	for _, nodes := range nodesPerLayer {
+		header = append(header, uint32(nodes))
+	}
+	if err := binary.Write(w, byteOrder, header); err != nil {
+		return err
+	}
+	activations := make([]uint8, 0, len(nodesPerLayer)-1)
+	for _, activation := range net.GetActivations() {
+		activations = append(activations, uint8(activation))
+	}
+	if err := binary.Write(w, byteOrder, activations); err != nil {
+		return err
+	}
+
+	if err := encodeLabels(w, net.outputLabels); err != nil {
+		return err
+	}
+
+	for _, parameters := range net.getLayersParameters() {
+		var err error
+		if precision == Float32Precision {
+			parameters32 := make([]float32, len(parameters))
+			for i, parameter := range parameters {
+				parameters32[i] = float32(parameter)
+			}
+			err = binary.Write(w, byteOrder, parameters32)
+		} else {
+			err = binary.Write(w, byteOrder, parameters)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads the network written by EncodeBinary with the same precision.
+// If the data ends too early io.ErrUnexpectedEOF is returned
+func DecodeBinary(r io.Reader, precision FloatPrecision) (Network, error) {
+	var numberOfLayers uint32
+	if err := readBinary(r, &numberOfLayers); err != nil {
+		return Network{}, err
+	}
+	if numberOfLayers == 0 || numberOfLayers > maxBinaryLayers {
+		return Network{}, errors.New("incorrect number of layers")
+	}
+	nodes := make([]uint32, numberOfLayers)
+	if err := readBinary(r, nodes); err != nil {
+		return Network{}, err
+	}
+	nodesPerLayer := make([]int, numberOfLayers)
+	for i, v := range nodes {
+		if v == 0 || v > maxBinaryNodes {
+			return Network{}, errors.New("incorrect number of nodes")
+		}
+		nodesPerLayer[i] = int(v)
+	}
+
+	activationBytes := make([]uint8, numberOfLayers-1)
+	if err := readBinary(r, activationBytes); err != nil {
+		return Network{}, err
+	}
+	activations := make([]Activation, len(activationBytes))
+	for i, v := range activationBytes {
+		activations[i] = Activation(v)
+		if !activations[i].IsValid() {
+			return Network{}, errors.New("unknown activation function")
+		}
+	}
+
+	outputLabels, err := decodeLabels(r, nodesPerLayer[len(nodesPerLayer)-1])
+	if err != nil {
+		return Network{}, err
+	}
+
+	// parameters are read before the network is created so a truncated data can't make it allocate too much memory
+	layersParameters := make([][]float64, 0, len(nodesPerLayer)-1)
+	for i := 1; i < len(nodesPerLayer); i++ {
+		parameters, err := readFloats(r, nodesPerLayer[i]*(1+nodesPerLayer[i-1]), precision)
+		if err != nil {
+			return Network{}, err
+		}
+		layersParameters = append(layersParameters, parameters)
+	}
+
+	var net Network
+	net.InitializeEmptyNetwork(nodesPerLayer, outputLabels, activations)
+	for i, parameters := range layersParameters {
+		net.setLayerParameters(i+1, parameters)
+	}
+	return net, nil
+}
+
+// returns for every layer except the input one its biases followed by weights of every node
+// from all previous layer's nodes
+func (net *Network) getLayersParameters() [][]float64 {
+	layersParameters := make([][]float64, 0, len(net.layers)-1)
+	for i := 1; i < len(net.layers); i++ {
+		parameters := make([]float64, 0, len(net.layers[i].biases)+len(net.layers[i].weights))
+		parameters = append(parameters, net.layers[i].biases...)
+		parameters = append(parameters, net.layers[i].weights...)
+		layersParameters = append(layersParameters, parameters)
+	}
+	return layersParameters
+}
+
+// sets parameters of the layer ordered the same way as by getLayersParameters
+func (net *Network) setLayerParameters(layerIndex int, parameters []float64) {
+	myLayer := &net.layers[layerIndex]
+	copy(myLayer.weights, parameters[copy(myLayer.biases, parameters):])
+}
+
+func encodeLabels(w io.Writer, labels []string) error {
+	if labels == nil {
+		return binary.Write(w, byteOrder, uint8(0))
+	}
+	if err := binary.Write(w, byteOrder, uint8(1)); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		if err := binary.Write(w, byteOrder, uint32(len(label))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeLabels(r io.Reader, numberOfLabels int) ([]string, error) {
+	var hasLabels uint8
+	if err := readBinary(r, &hasLabels); err != nil {
+		return nil, err
+	}
+	switch hasLabels {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, errors.New("incorrect output labels flag")
+	}
+
+	labels := make([]string, 0, numberOfLabels)
+	for i := 0; i < numberOfLabels; i++ {
+		var length uint32
+		if err := readBinary(r, &length); err != nil {
+			return nil, err
+		}
+		if length > maxBinaryLabelLength {
+			return nil, errors.New("output label is too long")
+		}
+		label := make([]byte, length)
+		if err := readBinary(r, label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, string(label))
+	}
+	return labels, nil
+}
+
+// reads the given amount of floats in chunks
+func readFloats(r io.Reader, amount int, precision FloatPrecision) ([]float64, error) {
+	values := make([]float64, 0, minInt(amount, binaryReadChunk))
+	for len(values) < amount {
+		chunk := minInt(amount-len(values), binaryReadChunk)
+		if precision == Float32Precision {
+			values32 := make([]float32, chunk)
+			if err := readBinary(r, values32); err != nil {
+				return nil, err
+			}
+			for _, v := range values32 {
+				values = append(values, float64(v))
+			}
+		} else {
+			values64 := make([]float64, chunk)
+			if err := readBinary(r, values64); err != nil {
+				return nil, err
+			}
+			values = append(values, values64...)
+		}
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("parameter isn't a finite number")
+		}
+	}
+	return values, nil
+}
+
+// reads the value and reports every too early end of the data as io.ErrUnexpectedEOF
+func readBinary(r io.Reader, data any) error {
+	err := binary.Read(r, byteOrder, data)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func minInt(first, second int) int {
+	if first < second {
+		return first
+	}
+	return second
+}
@@ -5,19 +5,48 @@ import (
 )
 
 type Data struct {
-	inputs         []float64
-	expectedOutput string
+	inputs          []float64
+	expectedOutput  string
+	expectedOutputs []string  // used instead of the expected output for multi-label classification
+	expectedValues  []float64 // used instead of the expected output for regression
 }
 
 func (dataSet *Data) SetData(inputs []float64, expectedOutput string) {
 	dataSet.inputs = inputs
 	dataSet.expectedOutput = expectedOutput
+	dataSet.expectedOutputs = nil
+	dataSet.expectedValues = nil
+}
+
+// sets the data with all labels which the inputs belong to instead of the single expected label
+func (dataSet *Data) SetMultiLabelData(inputs []float64, expectedOutputs []string) {
+	dataSet.inputs = inputs
+	dataSet.expectedOutput = ""
+	// an empty label set is still multi-label data so it can't be nil
+	dataSet.expectedOutputs = append(make([]string, 0, len(expectedOutputs)), expectedOutputs...)
+	dataSet.expectedValues = nil
+}
+
+// sets the data with values which output nodes should have instead of the expected label
+func (dataSet *Data) SetRegressionData(inputs []float64, expectedValues []float64) {
+	dataSet.inputs = inputs
+	dataSet.expectedOutput = ""
+	dataSet.expectedOutputs = nil
+	dataSet.expectedValues = expectedValues
 }
 
 func (dataSet *Data) GetExpOutput() string {
 	return dataSet.expectedOutput
 }
 
+func (dataSet *Data) GetExpOutputs() []string {
+	return dataSet.expectedOutputs
+}
+
+func (dataSet *Data) GetExpValues() []float64 {
+	return dataSet.expectedValues
+}
+
 func (dataSet *Data) GetInputs() []float64 {
 	return dataSet.inputs
 }
@@ -36,6 +65,14 @@ func (dataSets DataSets) GetSafeDataSetCopy(lock *sync.Mutex, index int) Data {
 	copy(inputCopy, inputs)
 
 	var dataCopy Data
-	dataCopy.SetData(inputCopy, expectedOutput)
+	if expectedValues := dataSets[index].GetExpValues(); expectedValues != nil {
+		expectedValuesCopy := make([]float64, len(expectedValues))
+		copy(expectedValuesCopy, expectedValues)
+		dataCopy.SetRegressionData(inputCopy, expectedValuesCopy)
+	} else if expectedOutputs := dataSets[index].GetExpOutputs(); expectedOutputs != nil {
+		dataCopy.SetMultiLabelData(inputCopy, expectedOutputs)
+	} else {
+		dataCopy.SetData(inputCopy, expectedOutput)
+	}
 	return dataCopy
 }
@@ -0,0 +1,32 @@
+package network
+
+// Nodes' values calculated by a single forward pass. Every calculation has its own values,
+// so the network is only read and can be used by many goroutines at once
+type forwardPass struct {
+	inputs [][]float64 // values of every layer's nodes before the activation
+	values [][]float64 // values of every layer's nodes after the activation, the input data for the input layer
+}
+
+// returns values for all nodes of the network allocated at once
+func (net *Network) newForwardPass() *forwardPass {
+	numberOfNodes := 0
+	for i := range net.layers {
+		numberOfNodes += net.layers[i].numberOfNodes
+	}
+
+	buffer := make([]float64, 2*numberOfNodes)
+	pass := forwardPass{
+		inputs: make([][]float64, len(net.layers)),
+		values: make([][]float64, len(net.layers)),
+	}
+	for i := range net.layers {
+		pass.inputs[i], buffer = cut(buffer, net.layers[i].numberOfNodes)
+		pass.values[i], buffer = cut(buffer, net.layers[i].numberOfNodes)
+	}
+	return &pass
+}
+
+// returns values of the output nodes
+func (pass *forwardPass) getOutputs() []float64 {
+	return pass.values[len(pass.values)-1]
+}
@@ -0,0 +1,46 @@
+package network
+
+// returns the amount of all biases and weights of the network
+func (net *Network) GetNumberOfParameters() int {
+	return len(net.parameters)
+}
+
+// Calculates derivatives of the data's cost measured by the loss with respect to every bias and weight of the network.
+// The gradients are ordered the same way as the parameters returned by GetRawParameters
+func (net *Network) CalculateGradients(data Data, loss Loss) []float64 {
+	pass := net.newForwardPass()
+	net.calculateOutput(pass, data.inputs)
+
+	// deltas are the derivatives of the cost with respect to the nodes' values before the activation
+	deltas := make([][]float64, len(net.layers))
+	lastLayer := len(net.layers) - 1
+	outputGradients := loss.Gradients(pass.getOutputs(), net.getExpectedOutputs(data))
+	deltas[lastLayer] = net.layers[lastLayer].activation.backward(pass.inputs[lastLayer], pass.values[lastLayer], outputGradients)
+	// the input layer's deltas aren't needed as it has nothing to learn before it
+	for i := lastLayer - 1; i > 0; i-- {
+		nextLayer := &net.layers[i+1]
+		outputGradients := make([]float64, net.layers[i].numberOfNodes)
+		for j := range outputGradients {
+			for k := range nextLayer.biases {
+				outputGradients[j] += nextLayer.getWeight(k, j) * deltas[i+1][k]
+			}
+		}
+		deltas[i] = net.layers[i].activation.backward(pass.inputs[i], pass.values[i], outputGradients)
+	}
+
+	gradients := make([]float64, net.GetNumberOfParameters())
+	offset := 0
+	for i := 1; i < len(net.layers); i++ {
+		numberOfNodes, fanIn := net.layers[i].numberOfNodes, net.layers[i].fanIn
+		biasGradients := gradients[offset : offset+numberOfNodes]
+		weightGradients := gradients[offset+numberOfNodes : offset+numberOfNodes+numberOfNodes*fanIn]
+		for j, delta := range deltas[i] {
+			biasGradients[j] = delta
+			for k, prevValue := range pass.values[i-1] {
+				weightGradients[j*fanIn+k] = prevValue * delta
+			}
+		}
+		offset += numberOfNodes + numberOfNodes*fanIn
+	}
+	return gradients
+}
@@ -0,0 +1,268 @@
+package network
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Initializer gives a layer its initial weights and biases.
+// The fan-in is the number of previous layer's nodes and the fan-out is the number of the layer's nodes
+type Initializer interface {
+	// returns weights of the layer, weights[j][k] connects the previous layer's node k with the layer's node j
+	InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64
+	// returns biases of the layer's nodes
+	InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64
+}
+
+// Draws weights and biases uniformly from [-Limit, Limit]
+type Uniform struct {
+	Limit float64
+}
+
+func (initializer Uniform) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return uniformWeights(random, fanIn, fanOut, initializer.Limit)
+}
+
+func (initializer Uniform) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return uniformValues(random, fanOut, initializer.Limit)
+}
+
+// Draws weights and biases from the normal distribution with the mean of 0
+type Normal struct {
+	StdDev float64
+}
+
+func (initializer Normal) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return normalWeights(random, fanIn, fanOut, initializer.StdDev)
+}
+
+func (initializer Normal) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return normalValues(random, fanOut, initializer.StdDev)
+}
+
+// Glorot's uniform initialization keeping the variance of values and gradients the same in both directions.
+// Suited for sigmoid, tanh and softmax layers. Biases are zero
+type XavierUniform struct{}
+
+func (XavierUniform) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return uniformWeights(random, fanIn, fanOut, math.Sqrt(6/float64(fanIn+fanOut)))
+}
+
+func (XavierUniform) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// Glorot's normal initialization. Biases are zero
+type XavierNormal struct{}
+
+func (XavierNormal) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return normalWeights(random, fanIn, fanOut, math.Sqrt(2/float64(fanIn+fanOut)))
+}
+
+func (XavierNormal) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// He's uniform initialization which makes up for the half of values zeroed by the ReLU family. Biases are zero
+type HeUniform struct{}
+
+func (HeUniform) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return uniformWeights(random, fanIn, fanOut, math.Sqrt(6/float64(fanIn)))
+}
+
+func (HeUniform) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// He's normal initialization. Biases are zero
+type HeNormal struct{}
+
+func (HeNormal) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return normalWeights(random, fanIn, fanOut, math.Sqrt(2/float64(fanIn)))
+}
+
+func (HeNormal) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// LeCun's uniform initialization keeping the variance of values the same. Biases are zero
+type LeCunUniform struct{}
+
+func (LeCunUniform) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return uniformWeights(random, fanIn, fanOut, math.Sqrt(3/float64(fanIn)))
+}
+
+func (LeCunUniform) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// LeCun's normal initialization. Biases are zero
+type LeCunNormal struct{}
+
+func (LeCunNormal) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return normalWeights(random, fanIn, fanOut, math.Sqrt(1/float64(fanIn)))
+}
+
+func (LeCunNormal) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// Makes the weights' matrix orthogonal, so its rows (or columns if there are more rows than columns)
+// are orthonormal. It keeps the values' norm in deep networks. Biases are zero
+type Orthogonal struct{}
+
+func (Orthogonal) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	if fanOut <= fanIn {
+		return orthonormalRows(random, fanOut, fanIn)
+	}
+	rows := orthonormalRows(random, fanIn, fanOut)
+	weights := make([][]float64, fanOut)
+	for j := range weights {
+		weights[j] = make([]float64, fanIn)
+		for k := range weights[j] {
+			weights[j][k] = rows[k][j]
+		}
+	}
+	return weights
+}
+
+func (Orthogonal) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// Uses weights of the wrapped initializer and zero biases
+type ZeroBias struct {
+	Initializer Initializer
+}
+
+func (initializer ZeroBias) InitializeWeights(random *rand.Rand, fanIn, fanOut int) [][]float64 {
+	return initializer.Initializer.InitializeWeights(random, fanIn, fanOut)
+}
+
+func (ZeroBias) InitializeBiases(random *rand.Rand, fanIn, fanOut int) []float64 {
+	return make([]float64, fanOut)
+}
+
+// returns the initializer suited for the activation, used by layers which weren't given any
+func DefaultInitializer(activation Activation) Initializer {
+	switch activation {
+	case ReLU, LeakyReLU, ELU, GELU, Softplus:
+		return HeUniform{}
+	default:
+		return XavierUniform{}
+	}
+}
+
+// Describes an initializer so it can be read from a configuration file
+type InitializerConfig struct {
+	// uniform, normal, xavierUniform, xavierNormal, heUniform, heNormal, leCunUniform, leCunNormal or orthogonal
+	Type string `json:"type"`
+	// used only by uniform
+	Limit float64 `json:"limit,omitempty"`
+	// used only by normal
+	StdDev float64 `json:"stdDev,omitempty"`
+	// wraps the initializer in ZeroBias
+	ZeroBias bool `json:"zeroBias,omitempty"`
+}
+
+// returns the described initializer
+func (config InitializerConfig) NewInitializer() (Initializer, error) {
+	var initializer Initializer
+	switch config.Type {
+	case "uniform":
+		if config.Limit <= 0 {
+			return nil, errors.New("uniform initializer's limit has to be bigger than 0")
+		}
+		initializer = Uniform{Limit: config.Limit}
+	case "normal":
+		if config.StdDev <= 0 {
+			return nil, errors.New("normal initializer's standard deviation has to be bigger than 0")
+		}
+		initializer = Normal{StdDev: config.StdDev}
+	case "xavierUniform":
+		initializer = XavierUniform{}
+	case "xavierNormal":
+		initializer = XavierNormal{}
+	case "heUniform":
+		initializer = HeUniform{}
+	case "heNormal":
+		initializer = HeNormal{}
+	case "leCunUniform":
+		initializer = LeCunUniform{}
+	case "leCunNormal":
+		initializer = LeCunNormal{}
+	case "orthogonal":
+		initializer = Orthogonal{}
+	default:
+		return nil, errors.New("unknown initializer: " + config.Type)
+	}
+	if config.ZeroBias {
+		initializer = ZeroBias{Initializer: initializer}
+	}
+	return initializer, nil
+}
+
+func uniformValues(random *rand.Rand, amount int, limit float64) []float64 {
+	values := make([]float64, amount)
+	for i := range values {
+		values[i] = (random.Float64()*2 - 1) * limit
+	}
+	return values
+}
+
+func normalValues(random *rand.Rand, amount int, stdDev float64) []float64 {
+	values := make([]float64, amount)
+	for i := range values {
+		values[i] = random.NormFloat64() * stdDev
+	}
+	return values
+}
+
+func uniformWeights(random *rand.Rand, fanIn, fanOut int, limit float64) [][]float64 {
+	weights := make([][]float64, fanOut)
+	for j := range weights {
+		weights[j] = uniformValues(random, fanIn, limit)
+	}
+	return weights
+}
+
+func normalWeights(random *rand.Rand, fanIn, fanOut int, stdDev float64) [][]float64 {
+	weights := make([][]float64, fanOut)
+	for j := range weights {
+		weights[j] = normalValues(random, fanIn, stdDev)
+	}
+	return weights
+}
+
+// returns numberOfRows orthonormal rows of the given length made from normally distributed ones
+// with the Gram-Schmidt process. There can't be more rows than their length
+func orthonormalRows(random *rand.Rand, numberOfRows, length int) [][]float64 {
+	rows := make([][]float64, 0, numberOfRows)
+	for len(rows) < numberOfRows {
+		row := normalValues(random, length, 1)
+		for _, previous := range rows {
+			dot := 0.0
+			for i := range row {
+				dot += row[i] * previous[i]
+			}
+			for i := range row {
+				row[i] -= dot * previous[i]
+			}
+		}
+		norm := 0.0
+		for _, v := range row {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		// a row almost dependent on the previous ones is drawn again
+		if norm < 1e-10 {
+			continue
+		}
+		for i := range row {
+			row[i] /= norm
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
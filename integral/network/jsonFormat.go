@@ -0,0 +1,119 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// The JSON representation of the network:
+//
+//	{
+//	  "layers": [
+//	    {"nodes": 3},
+//	    {"nodes": 2, "activation": "sigmoid", "biases": [0.1, -0.4], "weights": [[0.5, 1, -2], [0, 0.3, 0.7]]}
+//	  ],
+//	  "outputLabels": ["red", "notRed"]
+//	}
+//
+// The input layer has only the amount of nodes. Every other layer has its activation,
+// a bias for every node and for every node the weights of connections from all previous layer's nodes.
+// The self-adaptive mutation strength is saved as "mutationStrength" only if it is used
+type jsonNetwork struct {
+	Layers           []jsonLayer `json:"layers"`
+	OutputLabels     []string    `json:"outputLabels"`
+	MutationStrength float64     `json:"mutationStrength,omitempty"`
+}
+
+type jsonLayer struct {
+	Nodes      int         `json:"nodes"`
+	Activation *Activation `json:"activation,omitempty"`
+	Biases     []float64   `json:"biases,omitempty"`
+	Weights    [][]float64 `json:"weights,omitempty"`
+}
+
+func (net Network) MarshalJSON() ([]byte, error) {
+	var myJSON jsonNetwork
+	myJSON.OutputLabels = net.outputLabels
+	myJSON.MutationStrength = net.mutationStrength
+	myJSON.Layers = make([]jsonLayer, len(net.layers))
+	for i := range net.layers {
+		myJSON.Layers[i].Nodes = net.layers[i].numberOfNodes
+		if i == 0 {
+			continue
+		}
+
+		activation := net.layers[i].activation
+		myJSON.Layers[i].Activation = &activation
+		myJSON.Layers[i].Biases = append([]float64(nil), net.layers[i].biases...)
+		myJSON.Layers[i].Weights = make([][]float64, len(net.layers[i].biases))
+		for j := range myJSON.Layers[i].Weights {
+			myJSON.Layers[i].Weights[j] = append([]float64(nil), net.layers[i].getNodeWeights(j)...)
+		}
+	}
+	return json.Marshal(myJSON)
+}
+
+func (net *Network) UnmarshalJSON(data []byte) error {
+	var myJSON jsonNetwork
+	if err := json.Unmarshal(data, &myJSON); err != nil {
+		return err
+	}
+	if err := myJSON.validate(); err != nil {
+		return err
+	}
+
+	nodesPerLayer := make([]int, len(myJSON.Layers))
+	activations := make([]Activation, 0, len(myJSON.Layers)-1)
+	for i, myLayer := range myJSON.Layers {
+		nodesPerLayer[i] = myLayer.Nodes
+		if i != 0 {
+			activations = append(activations, *myLayer.Activation)
+		}
+	}
+
+	var newNet Network
+	newNet.InitializeEmptyNetwork(nodesPerLayer, myJSON.OutputLabels, activations)
+	for i := 1; i < len(myJSON.Layers); i++ {
+		copy(newNet.layers[i].biases, myJSON.Layers[i].Biases)
+		for j, weights := range myJSON.Layers[i].Weights {
+			copy(newNet.layers[i].getNodeWeights(j), weights)
+		}
+	}
+	newNet.mutationStrength = myJSON.MutationStrength
+	*net = newNet
+	return nil
+}
+
+// checks if the decoded network has a correct structure
+func (myJSON *jsonNetwork) validate() error {
+	if len(myJSON.Layers) == 0 {
+		return errors.New("the network has no layers")
+	}
+	if myJSON.MutationStrength < 0 {
+		return errors.New("mutation strength can't be negative")
+	}
+	for i, myLayer := range myJSON.Layers {
+		if myLayer.Nodes <= 0 {
+			return errors.New("number of nodes per layer can't be lower than 1")
+		}
+		if i == 0 {
+			if myLayer.Activation != nil || myLayer.Biases != nil || myLayer.Weights != nil {
+				return errors.New("the input layer can't have activation, biases or weights")
+			}
+			continue
+		}
+
+		if myLayer.Activation == nil {
+			return errors.New("layer's activation is missing")
+		}
+		if len(myLayer.Biases) != myLayer.Nodes || len(myLayer.Weights) != myLayer.Nodes {
+			return errors.New("number of layer's biases and weights has to be the same as number of its nodes")
+		}
+		for _, weights := range myLayer.Weights {
+			if len(weights) != myJSON.Layers[i-1].Nodes {
+				return errors.New("number of node's weights has to be the same as number of previous layer's nodes")
+			}
+		}
+	}
+	return nil
+}
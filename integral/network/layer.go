@@ -1,35 +1,77 @@
 package network
 
+import "math/rand"
+
 type layer struct {
-	nodes []node
+	numberOfNodes int
+	biases        []float64 // empty for the input layer
+	// weights of connections from the previous layer's nodes stored row by row,
+	// weights[j*fanIn+k] connects the previous layer's node k with the node j. Empty for the input layer
+	weights     []float64
+	fanIn       int // the number of previous layer's nodes
+	activation  Activation
+	initializer Initializer // if nil the default one for the activation is used
 }
 
-func (myLayer *layer) initializeLayer(numberOfNodes int, numberOfNextNodes int, prevLayer *layer, nextLayer *layer) {
-	myLayer.nodes = make([]node, numberOfNodes)
-	for i := range myLayer.nodes {
-		myLayer.nodes[i].initializeNode(prevLayer, nextLayer, numberOfNextNodes)
+// Initializes the layer with zeroed biases and weights. They are cut from the beginning
+// of the given parameters, which are then moved past them. The input layer (with the fan-in of 0) has no biases
+func (myLayer *layer) initializeEmptyLayer(numberOfNodes int, fanIn int, parameters *[]float64) {
+	myLayer.numberOfNodes = numberOfNodes
+	myLayer.fanIn = fanIn
+	if fanIn == 0 {
+		return
 	}
+	myLayer.biases, *parameters = cut(*parameters, numberOfNodes)
+	myLayer.weights, *parameters = cut(*parameters, numberOfNodes*fanIn)
 }
 
-func (myLayer *layer) initializeEmptyLayer(numberOfNodes int, numberOfNextNodes int, prevLayer *layer, nextLayer *layer) {
-	myLayer.nodes = make([]node, numberOfNodes)
-	for i := range myLayer.nodes {
-		myLayer.nodes[i].initializeEmptyNode(prevLayer, nextLayer, numberOfNextNodes)
-	}
+// returns the first amount of values, which can't be extended over the rest, and the rest
+func cut(values []float64, amount int) ([]float64, []float64) {
+	return values[:amount:amount], values[amount:]
 }
 
-func (myLayer *layer) calculateNextLayer() {
-	for i := range myLayer.nodes {
-		myLayer.nodes[i].calculateNextNodes()
+// gives the layer biases and weights taken from its initializer
+func (myLayer *layer) initializeParameters(random *rand.Rand) {
+	fanIn, fanOut := myLayer.fanIn, myLayer.numberOfNodes
+	initializer := myLayer.getInitializer()
+	weights := initializer.InitializeWeights(random, fanIn, fanOut)
+	biases := initializer.InitializeBiases(random, fanIn, fanOut)
+	copy(myLayer.biases, biases)
+	for j := range weights {
+		copy(myLayer.getNodeWeights(j), weights[j])
 	}
-	for i := range myLayer.nodes {
-		myLayer.nodes[i].addBias()
+}
+
+func (myLayer *layer) getInitializer() Initializer {
+	if myLayer.initializer == nil {
+		return DefaultInitializer(myLayer.activation)
 	}
+	return myLayer.initializer
+}
+
+// returns weights of connections from all previous layer's nodes to the node
+func (myLayer *layer) getNodeWeights(nodeIndex int) []float64 {
+	return myLayer.weights[nodeIndex*myLayer.fanIn : (nodeIndex+1)*myLayer.fanIn]
+}
+
+// returns the weight of the connection from the previous layer's node to the node
+func (myLayer *layer) getWeight(nodeIndex, prevNodeIndex int) float64 {
+	return myLayer.weights[nodeIndex*myLayer.fanIn+prevNodeIndex]
+}
+
+func (myLayer *layer) setWeight(nodeIndex, prevNodeIndex int, weight float64) {
+	myLayer.weights[nodeIndex*myLayer.fanIn+prevNodeIndex] = weight
 }
 
-// uses on all nodes the sigmoid funcion
-func (myLayer *layer) sigmoidizeNodes() {
-	for i := range myLayer.nodes {
-		myLayer.nodes[i].sigmoidize()
+// Calculates nodes' values from the previous layer's values, adds biases and uses the layer's activation function.
+// The values before and after the activation are written to the given slices
+func (myLayer *layer) calculateValues(prevValues, inputs, values []float64) {
+	for j := range myLayer.biases {
+		value := 0.0
+		for k, weight := range myLayer.getNodeWeights(j) {
+			value += prevValues[k] * weight
+		}
+		inputs[j] = value + myLayer.biases[j]
 	}
+	myLayer.activation.activate(inputs, values)
 }
@@ -0,0 +1,139 @@
+package network
+
+import "math"
+
+// keeps logarithms of the cross entropies from reaching infinity
+const lossEpsilon = 1e-12
+
+// Loss measures how far network's outputs are from the expected ones
+type Loss interface {
+	// returns the cost of a single data set
+	Cost(outputs, expected []float64) float64
+	// returns derivatives of the cost with respect to every output
+	Gradients(outputs, expected []float64) []float64
+}
+
+// The average of squared differences between the outputs and the expected values
+type MeanSquaredError struct{}
+
+func (MeanSquaredError) Cost(outputs, expected []float64) float64 {
+	cost := 0.0
+	for i := range outputs {
+		cost += math.Pow(outputs[i]-expected[i], 2)
+	}
+	return cost / float64(len(outputs))
+}
+
+func (MeanSquaredError) Gradients(outputs, expected []float64) []float64 {
+	gradients := make([]float64, len(outputs))
+	for i := range outputs {
+		gradients[i] = 2 * (outputs[i] - expected[i]) / float64(len(outputs))
+	}
+	return gradients
+}
+
+// Cross entropy calculated independently for every output. The outputs have to be between 0 and 1
+type BinaryCrossEntropy struct{}
+
+func (BinaryCrossEntropy) Cost(outputs, expected []float64) float64 {
+	cost := 0.0
+	for i := range outputs {
+		output := clampProbability(outputs[i])
+		cost -= expected[i]*math.Log(output) + (1-expected[i])*math.Log(1-output)
+	}
+	return cost / float64(len(outputs))
+}
+
+func (BinaryCrossEntropy) Gradients(outputs, expected []float64) []float64 {
+	gradients := make([]float64, len(outputs))
+	for i := range outputs {
+		output := clampProbability(outputs[i])
+		gradients[i] = (output - expected[i]) / (output * (1 - output)) / float64(len(outputs))
+	}
+	return gradients
+}
+
+// Cross entropy of the outputs treated as one probability distribution.
+// It should be paired with the softmax output layer
+type CategoricalCrossEntropy struct{}
+
+func (CategoricalCrossEntropy) Cost(outputs, expected []float64) float64 {
+	cost := 0.0
+	for i := range outputs {
+		cost -= expected[i] * math.Log(clampProbability(outputs[i]))
+	}
+	return cost
+}
+
+func (CategoricalCrossEntropy) Gradients(outputs, expected []float64) []float64 {
+	gradients := make([]float64, len(outputs))
+	for i := range outputs {
+		gradients[i] = -expected[i] / clampProbability(outputs[i])
+	}
+	return gradients
+}
+
+// Penalizes outputs which aren't on the right side of 0 with the margin of 1.
+// Expected values of 1 are treated as the positive class and the rest as the negative one
+type Hinge struct{}
+
+func (Hinge) Cost(outputs, expected []float64) float64 {
+	cost := 0.0
+	for i := range outputs {
+		cost += math.Max(0, 1-hingeSign(expected[i])*outputs[i])
+	}
+	return cost / float64(len(outputs))
+}
+
+func (Hinge) Gradients(outputs, expected []float64) []float64 {
+	gradients := make([]float64, len(outputs))
+	for i := range outputs {
+		sign := hingeSign(expected[i])
+		if 1-sign*outputs[i] > 0 {
+			gradients[i] = -sign / float64(len(outputs))
+		}
+	}
+	return gradients
+}
+
+// Squared error for differences smaller than Delta and absolute error for the bigger ones.
+// It is less sensitive to outliers than the mean squared error
+type Huber struct {
+	Delta float64
+}
+
+func (loss Huber) Cost(outputs, expected []float64) float64 {
+	cost := 0.0
+	for i := range outputs {
+		difference := math.Abs(outputs[i] - expected[i])
+		if difference <= loss.Delta {
+			cost += 0.5 * difference * difference
+		} else {
+			cost += loss.Delta * (difference - 0.5*loss.Delta)
+		}
+	}
+	return cost / float64(len(outputs))
+}
+
+func (loss Huber) Gradients(outputs, expected []float64) []float64 {
+	gradients := make([]float64, len(outputs))
+	for i := range outputs {
+		difference := outputs[i] - expected[i]
+		if math.Abs(difference) > loss.Delta {
+			difference = math.Copysign(loss.Delta, difference)
+		}
+		gradients[i] = difference / float64(len(outputs))
+	}
+	return gradients
+}
+
+func clampProbability(value float64) float64 {
+	return math.Min(math.Max(value, lossEpsilon), 1-lossEpsilon)
+}
+
+func hingeSign(expected float64) float64 {
+	if expected == 1 {
+		return 1
+	}
+	return -1
+}
@@ -1,121 +1,291 @@
 package network
 
 import (
-	"math"
+	"context"
+	"math/rand"
 	"sync"
 )
 
 type Network struct {
 	layers       []layer
+	parameters   []float64 // biases and weights of all layers, layers' slices point into it
 	cost         float64
 	outputLabels []string
+	// the self-adaptive mutation strength inherited by the network's children, 0 if it isn't used
+	mutationStrength float64
 }
 
-func (net *Network) InitializeNetwork(nodesPerLayer []int, outputLabels []string) {
-	net.outputLabels = outputLabels
-
-	net.layers = make([]layer, len(nodesPerLayer))
-	if len(net.layers) == 1 {
-		net.layers[0].initializeLayer(nodesPerLayer[0], 0, nil, nil)
-		return
-	}
-
-	net.layers[0].initializeLayer(nodesPerLayer[0], nodesPerLayer[1], nil, &net.layers[1])
-	for i := 1; i < len(net.layers)-1; i++ {
-		net.layers[i].initializeLayer(nodesPerLayer[i], nodesPerLayer[i+1], &net.layers[i-1], &net.layers[i+1])
+// Initializes the network with weights and biases given by the initializers which take random numbers from the given source.
+// Activations and initializers are given for every layer except the input one. If activations are nil, sigmoid is used.
+// If initializers or any of them are nil, the default one for the layer's activation is used
+func (net *Network) InitializeNetwork(random *rand.Rand, nodesPerLayer []int, outputLabels []string, activations []Activation, initializers []Initializer) {
+	net.InitializeEmptyNetwork(nodesPerLayer, outputLabels, activations)
+	net.setInitializers(initializers)
+	for i := 1; i < len(net.layers); i++ {
+		net.layers[i].initializeParameters(random)
 	}
-	net.layers[len(net.layers)-1].initializeLayer(nodesPerLayer[len(net.layers)-1], 0, &net.layers[len(net.layers)-2], nil)
 }
 
-func (net *Network) InitializeEmptyNetwork(nodesPerLayer []int, outputLabels []string) {
+// Initializes the network with zeroed weights and biases.
+// Activations are given for every layer except the input one. If they are nil, sigmoid is used
+func (net *Network) InitializeEmptyNetwork(nodesPerLayer []int, outputLabels []string, activations []Activation) {
 	net.outputLabels = outputLabels
-	net.layers = make([]layer, len(nodesPerLayer))
-	if len(net.layers) == 1 {
-		net.layers[0].initializeEmptyLayer(nodesPerLayer[0], 0, nil, nil)
-		return
+	// the input layer has no biases nor weights
+	numberOfParameters := 0
+	for i := 1; i < len(nodesPerLayer); i++ {
+		numberOfParameters += nodesPerLayer[i] + nodesPerLayer[i]*nodesPerLayer[i-1]
 	}
 
-	net.layers[0].initializeEmptyLayer(nodesPerLayer[0], nodesPerLayer[1], nil, &net.layers[1])
-	for i := 1; i < len(net.layers)-1; i++ {
-		net.layers[i].initializeEmptyLayer(nodesPerLayer[i], nodesPerLayer[i+1], &net.layers[i-1], &net.layers[i+1])
+	// all parameters are kept in one slice, so the network is allocated and copied at once
+	net.parameters = make([]float64, numberOfParameters)
+	parameters := net.parameters
+	net.layers = make([]layer, len(nodesPerLayer))
+	for i, nodes := range nodesPerLayer {
+		fanIn := 0
+		if i != 0 {
+			fanIn = nodesPerLayer[i-1]
+		}
+		net.layers[i].initializeEmptyLayer(nodes, fanIn, &parameters)
 	}
-	net.layers[len(net.layers)-1].initializeEmptyLayer(nodesPerLayer[len(net.layers)-1], 0, &net.layers[len(net.layers)-2], nil)
+	net.setActivations(activations)
 }
 
 // returns the structure of the network - number of layers and nodes per layer
 func (net *Network) GetNetworkStructure() []int {
 	nodesPerLayer := make([]int, len(net.layers))
 	for i := range nodesPerLayer {
-		nodesPerLayer[i] = len(net.layers[i].nodes)
+		nodesPerLayer[i] = net.layers[i].numberOfNodes
 	}
 	return nodesPerLayer
 }
 
+// sets activations of all layers except the input one
+func (net *Network) setActivations(activations []Activation) {
+	for i := 1; i < len(net.layers); i++ {
+		if activations == nil {
+			net.layers[i].activation = Sigmoid
+		} else {
+			net.layers[i].activation = activations[i-1]
+		}
+	}
+}
+
+// sets initializers of all layers except the input one
+func (net *Network) setInitializers(initializers []Initializer) {
+	if initializers == nil {
+		return
+	}
+	for i := 1; i < len(net.layers); i++ {
+		net.layers[i].initializer = initializers[i-1]
+	}
+}
+
+// returns initializers of all layers except the input one
+func (net *Network) GetInitializers() []Initializer {
+	initializers := make([]Initializer, 0, len(net.layers)-1)
+	for i := 1; i < len(net.layers); i++ {
+		initializers = append(initializers, net.layers[i].getInitializer())
+	}
+	return initializers
+}
+
+// returns activations of all layers except the input one
+func (net *Network) GetActivations() []Activation {
+	activations := make([]Activation, 0, len(net.layers)-1)
+	for i := 1; i < len(net.layers); i++ {
+		activations = append(activations, net.layers[i].activation)
+	}
+	return activations
+}
+
 // returns network's output labels
 func (net *Network) GetOutputLabels() []string {
 	return net.outputLabels
 }
 
-// for given input it calculates the values of output nodes
-// after this function these nodes' values are ready to be exratced
-func (net *Network) calculateOutput(inputData []float64) {
-	// set input data to the input nodes
-	for i := range net.layers[0].nodes {
-		net.layers[0].nodes[i].value = inputData[i]
+// returns whether both networks have the same structure, output labels and activations
+func (net *Network) HasSameArchitecture(other *Network) bool {
+	if len(net.layers) != len(other.layers) || len(net.outputLabels) != len(other.outputLabels) {
+		return false
+	}
+	for i := range net.layers {
+		if net.layers[i].numberOfNodes != other.layers[i].numberOfNodes {
+			return false
+		}
+		// the input layer has no activation
+		if i > 0 && net.layers[i].activation != other.layers[i].activation {
+			return false
+		}
+	}
+	for i := range net.outputLabels {
+		if net.outputLabels[i] != other.outputLabels[i] {
+			return false
+		}
 	}
+	return true
+}
 
-	// only calculates the next layer for first layer as it shouldn't be sigmoidized
-	net.layers[0].calculateNextLayer()
-	for i := 1; i < len(net.layers)-1; i++ {
-		net.layers[i].sigmoidizeNodes()
-		net.layers[i].calculateNextLayer()
+// for given input it calculates values of all nodes and writes them to the forward pass
+// after this function output nodes' values are ready to be exratced from it
+func (net *Network) calculateOutput(pass *forwardPass, inputData []float64) {
+	copy(pass.values[0], inputData)
+	for i := 1; i < len(net.layers); i++ {
+		net.layers[i].calculateValues(pass.values[i-1], pass.inputs[i], pass.values[i])
 	}
-	// only sigmoidized the last layer as there are no next layers
-	net.layers[len(net.layers)-1].sigmoidizeNodes()
 }
 
-// calculates network's average cost for given data sets
-func (net *Network) CalculateCost(lock *sync.Mutex, dataSets DataSets) {
+// calculates network's average cost for given data sets using the given loss
+func (net *Network) CalculateCost(lock *sync.Mutex, dataSets DataSets, loss Loss) {
+	net.cost, _ = net.averageCost(context.Background(), lock, dataSets, loss)
+}
+
+// Calculates network's average cost like CalculateCost, but stops when the context is done.
+// Then the network's cost isn't changed and the context's error is returned
+func (net *Network) CalculateCostContext(ctx context.Context, lock *sync.Mutex, dataSets DataSets, loss Loss) error {
+	cost, err := net.averageCost(ctx, lock, dataSets, loss)
+	if err != nil {
+		return err
+	}
+	net.cost = cost
+	return nil
+}
+
+// Returns network's average cost for given data sets using the given loss.
+// Unlike CalculateCost it doesn't change the network's cost
+func (net *Network) GetAverageCost(dataSets DataSets, loss Loss) float64 {
+	cost, _ := net.averageCost(context.Background(), &sync.Mutex{}, dataSets, loss)
+	return cost
+}
+
+// the context is checked every this many data sets
+const contextCheckInterval = 64
+
+func (net *Network) averageCost(ctx context.Context, lock *sync.Mutex, dataSets DataSets, loss Loss) (float64, error) {
 	combinedCost := 0.0
+	pass := net.newForwardPass()
 	for i := range dataSets {
+		if i%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
 		data := dataSets.GetSafeDataSetCopy(lock, i)
-		for key, value := range net.GetOutputsMap(data.inputs) {
-			if key == data.expectedOutput {
-				combinedCost += math.Pow(1-value, 2) //FIXME: make it usable for something other than simgoid
-			} else {
-				combinedCost += math.Pow(value, 2)
+		net.calculateOutput(pass, data.inputs)
+		combinedCost += loss.Cost(pass.getOutputs(), net.getExpectedOutputs(data))
+	}
+	return combinedCost / float64(len(dataSets)), nil
+}
+
+// Returns the fraction of correct predictions for given data sets. For classification the best output node
+// has to have the expected label. For multi-label classification every output node is a separate prediction
+// which is correct if it is above 0.5 only for the expected labels. Regression data has no accuracy, so it is 0
+func (net *Network) GetAccuracy(dataSets DataSets) float64 {
+	correct, all := 0, 0
+	pass := net.newForwardPass()
+	for _, data := range dataSets {
+		if data.expectedValues != nil {
+			continue
+		}
+		net.calculateOutput(pass, data.inputs)
+		outputs := pass.getOutputs()
+		expectedOutputs := net.getExpectedOutputs(data)
+		if data.expectedOutputs != nil {
+			for i, output := range outputs {
+				if (output > 0.5) == (expectedOutputs[i] == 1) {
+					correct++
+				}
+				all++
 			}
+			continue
 		}
+
+		bestIndex := 0
+		for i, output := range outputs {
+			if output > outputs[bestIndex] {
+				bestIndex = i
+			}
+		}
+		if expectedOutputs[bestIndex] == 1 {
+			correct++
+		}
+		all++
+	}
+	if all == 0 {
+		return 0
+	}
+	return float64(correct) / float64(all)
+}
+
+// returns values which output nodes should have for the given data.
+// For classification it is 1 for the nodes with the data's expected labels and 0 for the rest
+func (net *Network) getExpectedOutputs(data Data) []float64 {
+	if data.expectedValues != nil {
+		return data.expectedValues
 	}
 
-	net.cost = combinedCost / float64(len(dataSets))
+	expectedOutputs := make([]float64, len(net.outputLabels))
+	for i, label := range net.outputLabels {
+		if data.expectedOutputs == nil {
+			if label == data.expectedOutput {
+				expectedOutputs[i] = 1
+			}
+			continue
+		}
+		for _, expectedOutput := range data.expectedOutputs {
+			if label == expectedOutput {
+				expectedOutputs[i] = 1
+				break
+			}
+		}
+	}
+	return expectedOutputs
 }
 
 func (net *Network) GetCost() float64 {
 	return net.cost
 }
 
+// Calculates the output and returns values of all output nodes.
+// It doesn't change the network, so it can be called by many goroutines at once
+func (net *Network) GetOutputs(inputData []float64) []float64 {
+	pass := net.newForwardPass()
+	net.calculateOutput(pass, inputData)
+	return pass.getOutputs()
+}
+
+// Calculates outputs for all inputs reusing the same values for every calculation.
+// The outputs are in the inputs' order and are allocated at once
+func (net *Network) GetOutputsBatch(inputs [][]float64) [][]float64 {
+	numberOfOutputs := net.layers[len(net.layers)-1].numberOfNodes
+	buffer := make([]float64, len(inputs)*numberOfOutputs)
+	outputs := make([][]float64, len(inputs))
+	pass := net.newForwardPass()
+	for i, input := range inputs {
+		net.calculateOutput(pass, input)
+		outputs[i], buffer = cut(buffer, numberOfOutputs)
+		copy(outputs[i], pass.getOutputs())
+	}
+	return outputs
+}
+
 // Calculates the output and returns a map where for each output node its lalbel
 // is the key and value is the map value
 func (net *Network) GetOutputsMap(inputData []float64) map[string]float64 {
-	net.calculateOutput(inputData)
-
 	resultMap := make(map[string]float64)
-	for i, node := range net.layers[len(net.layers)-1].nodes {
-		resultMap[net.outputLabels[i]] = node.value
+	for i, value := range net.GetOutputs(inputData) {
+		resultMap[net.outputLabels[i]] = value
 	}
 	return resultMap
 }
 
 // Calculates the output and returns a label of the best output node and its value
 func (net *Network) GetBestOutput(inputData []float64) (string, float64) {
-	net.calculateOutput(inputData)
-
-	bestValue := 0.0
+	outputs := net.GetOutputs(inputData)
+	bestValue := outputs[0]
 	labelIndex := 0
-	for i, node := range net.layers[len(net.layers)-1].nodes {
-		if node.value > bestValue {
-			bestValue = node.value
+	for i, value := range outputs {
+		if value > bestValue {
+			bestValue = value
 			labelIndex = i
 		}
 	}
@@ -123,57 +293,103 @@ func (net *Network) GetBestOutput(inputData []float64) (string, float64) {
 	return net.outputLabels[labelIndex], bestValue
 }
 
-// returns weight and bias of requested node
+// returns bias of requested node. The input layer's nodes have no biases, so for them it is always 0
 func (net *Network) GetNodeBias(layerIndex, nodeIndex int) float64 {
-	bias := net.layers[layerIndex].nodes[nodeIndex].bias
-	return bias
+	if layerIndex == 0 {
+		return 0
+	}
+	return net.layers[layerIndex].biases[nodeIndex]
 }
 
-// returns weight of requested node
+// returns weight of the connection from the requested node to the next layer's node with the weight's index
 func (net *Network) GetNodeWeight(layerIndex, nodeIndex, weightIndex int) float64 {
-	weight := net.layers[layerIndex].nodes[nodeIndex].weights[weightIndex]
-	return weight
+	return net.layers[layerIndex+1].getWeight(weightIndex, nodeIndex)
 }
 
-// sets bias of requested node if arguments are correct
+// sets bias of requested node if arguments are correct. The input layer's nodes have no biases, so it is ignored for them
 func (net *Network) SetNodeBias(layerIndex, nodeIndex int, newBias float64) {
-	net.layers[layerIndex].nodes[nodeIndex].bias = newBias
+	if layerIndex == 0 {
+		return
+	}
+	net.layers[layerIndex].biases[nodeIndex] = newBias
 }
 
-// sets weight of requested node's weight if arguments are correct
+// sets weight of the connection from the requested node to the next layer's node with the weight's index
 func (net *Network) SetNodeWeight(layerIndex, nodeIndex, weightIndex int, newWeight float64) {
-	net.layers[layerIndex].nodes[nodeIndex].weights[weightIndex] = newWeight
-}
-
-// returns a network with the same structure, wieghts and biases
-// func (net *Network) CopyNetwork() (copy Network, err error) {
-// 	nodesPerLayer := net.GetNetworkStructure()
-// 	copy.InitializeNetwork(nodesPerLayer, net.outputLabels)
-// 	// iterating over all layers
-// 	for i := 0; i < len(nodesPerLayer); i++ {
-// 		// iterating over all nodes in a layer
-// 		for j := 0; j < nodesPerLayer[i]; j++ {
-// 			bias, err := net.GetNodeBias(i, j)
-// 			if err != nil {
-// 				return copy, err
-// 			}
-// 			err = copy.SetNodeBias(i, j, bias)
-// 			if err != nil {
-// 				return copy, err
-// 			}
-
-// 			// iteratig over all weights
-// 			for k := 0; i != len(nodesPerLayer)-1 && k < nodesPerLayer[i+1]; k++ {
-// 				weight, err := net.GetNodeWeight(i, j, k)
-// 				if err != nil {
-// 					return copy, err
-// 				}
-// 				err = copy.SetNodeWeight(i, j, k, weight)
-// 				if err != nil {
-// 					return copy, err
-// 				}
-// 			}
-// 		}
-// 	}
-// 	return copy, nil
-// }
+	net.layers[layerIndex+1].setWeight(weightIndex, nodeIndex, newWeight)
+}
+
+// Returns all biases and weights of the network without copying them, so changing them changes the network.
+// For every layer its biases come first and then the weights of connections to its nodes, node by node.
+// They are ordered the same way for all networks of the same structure
+func (net *Network) GetRawParameters() []float64 {
+	return net.parameters
+}
+
+// Returns indexes of raw parameters of every layer except the input one, which has no parameters
+func (net *Network) GetLayerParameterIndexes() [][]int {
+	indexes := make([][]int, 0, len(net.layers)-1)
+	offset := 0
+	for i := 1; i < len(net.layers); i++ {
+		layerIndexes := make([]int, len(net.layers[i].biases)+len(net.layers[i].weights))
+		for j := range layerIndexes {
+			layerIndexes[j] = offset + j
+		}
+		indexes = append(indexes, layerIndexes)
+		offset += len(layerIndexes)
+	}
+	return indexes
+}
+
+// Returns indexes of raw parameters of every node - its bias and weights of connections from all previous layer's nodes.
+// Nodes are ordered layer by layer, the input layer's nodes have no parameters
+func (net *Network) GetNodeParameterIndexes() [][]int {
+	var indexes [][]int
+	offset := 0
+	for i := 1; i < len(net.layers); i++ {
+		numberOfNodes, fanIn := net.layers[i].numberOfNodes, net.layers[i].fanIn
+		for j := 0; j < numberOfNodes; j++ {
+			nodeIndexes := make([]int, 0, 1+fanIn)
+			nodeIndexes = append(nodeIndexes, offset+j)
+			for k := 0; k < fanIn; k++ {
+				nodeIndexes = append(nodeIndexes, offset+numberOfNodes+j*fanIn+k)
+			}
+			indexes = append(indexes, nodeIndexes)
+		}
+		offset += numberOfNodes + numberOfNodes*fanIn
+	}
+	return indexes
+}
+
+// returns the self-adaptive mutation strength, 0 if it isn't used
+func (net *Network) GetMutationStrength() float64 {
+	return net.mutationStrength
+}
+
+func (net *Network) SetMutationStrength(strength float64) {
+	net.mutationStrength = strength
+}
+
+// returns a network with the same structure, wieghts, biases and cost which doesn't share any memory with this one
+func (net *Network) CopyNetwork() Network {
+	myCopy := net.CopyEmptyNetwork()
+	copy(myCopy.parameters, net.parameters)
+	myCopy.cost = net.cost
+	myCopy.mutationStrength = net.mutationStrength
+	return myCopy
+}
+
+// returns a network with the same structure and zeroed weights and biases which doesn't share any memory with this one
+func (net *Network) CopyEmptyNetwork() Network {
+	var outputLabels []string
+	if net.outputLabels != nil {
+		outputLabels = append(make([]string, 0, len(net.outputLabels)), net.outputLabels...)
+	}
+
+	var myCopy Network
+	myCopy.InitializeEmptyNetwork(net.GetNetworkStructure(), outputLabels, net.GetActivations())
+	for i := range net.layers {
+		myCopy.layers[i].initializer = net.layers[i].initializer
+	}
+	return myCopy
+}
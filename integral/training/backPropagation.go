@@ -0,0 +1,31 @@
+package training
+
+import "context"
+
+// Trains the first network (the best one after the evolution) using the gradient descent until the current epoch ends.
+// A new epoch is started first if the previous one has ended. Its weights and biases are updated
+// by the trainer's optimizer with the gradients averaged over every batch.
+// If the context is done the training stops before the next batch and the context's error is returned
+func (trainer *Trainer) backPropagationTraining(ctx context.Context, batchSize int) error {
+	if trainer.epochEnded(batchSize) {
+		trainer.startEpoch()
+	}
+	net := &trainer.networks[0]
+	for batch, ok := trainer.nextBatch(batchSize); ok; batch, ok = trainer.nextBatch(batchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		gradients := net.CalculateGradients(batch[0], trainer.loss)
+		for _, data := range batch[1:] {
+			for i, gradient := range net.CalculateGradients(data, trainer.loss) {
+				gradients[i] += gradient
+			}
+		}
+		for i := range gradients {
+			gradients[i] /= float64(len(batch))
+		}
+
+		trainer.optimizer.Update(net.GetRawParameters(), gradients)
+	}
+	return nil
+}
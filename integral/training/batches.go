@@ -0,0 +1,118 @@
+package training
+
+import (
+	"errors"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// Determines how the training data is split into batches. An epoch ends when all training data sets were used once.
+// It goes on over Train calls and a new one is started only when it ends or different training data is given
+type BatchConfig struct {
+	// The number of data sets in a batch. If it is 0 the evolution uses all training data in every generation
+	// and the back propagation updates the network after every data set.
+	// The evolution measures the cost on a single batch per generation and the back propagation
+	// updates the network once per batch and goes through the whole epoch in every iteration
+	BatchSize int `json:"batchSize"`
+	// the training data sets are shuffled at the start of every epoch
+	Shuffle bool `json:"shuffle"`
+	// the last batch of an epoch is skipped if it is smaller than the batch size
+	DropLast bool `json:"dropLast"`
+}
+
+// checks if the batch size isn't negative
+func (config BatchConfig) Validate() error {
+	if config.BatchSize < 0 {
+		return errors.New("batch size can't be negative")
+	}
+	return nil
+}
+
+// returns the batch size used by the algorithm
+func (config BatchConfig) getBatchSize(algorithm Algorithm, numberOfDataSets int) int {
+	if config.BatchSize != 0 {
+		return config.BatchSize
+	}
+	if algorithm == BackPropagation {
+		return 1
+	}
+	return numberOfDataSets
+}
+
+// Sets the training data. The current epoch goes on if it is the same data, otherwise a new one is started
+// at the next batch. A resumed trainer doesn't know the data it was trained on, so it only compares their number
+func (trainer *Trainer) setTrainDataSets(dataSets network.DataSets) {
+	sameData := len(dataSets) == len(trainer.epochOrder) &&
+		(trainer.trainDataSets == nil || &trainer.trainDataSets[0] == &dataSets[0])
+	trainer.trainDataSets = dataSets
+	if !sameData {
+		trainer.epochOrder = nil
+		trainer.epochPosition = 0
+	}
+}
+
+// starts a new epoch, shuffling the order of the training data sets if it is configured
+func (trainer *Trainer) startEpoch() {
+	if len(trainer.epochOrder) != len(trainer.trainDataSets) {
+		trainer.epochOrder = make([]int, len(trainer.trainDataSets))
+	}
+	for i := range trainer.epochOrder {
+		trainer.epochOrder[i] = i
+	}
+	if trainer.batch.Shuffle {
+		trainer.random.Shuffle(len(trainer.epochOrder), func(i, j int) {
+			trainer.epochOrder[i], trainer.epochOrder[j] = trainer.epochOrder[j], trainer.epochOrder[i]
+		})
+	}
+	trainer.epochPosition = 0
+}
+
+// returns the next batch of the current epoch or false if the epoch has ended
+func (trainer *Trainer) nextBatch(batchSize int) (network.DataSets, bool) {
+	if trainer.epochEnded(batchSize) {
+		return nil, false
+	}
+	remaining := len(trainer.epochOrder) - trainer.epochPosition
+	if batchSize > remaining {
+		batchSize = remaining
+	}
+
+	start := trainer.epochPosition
+	trainer.epochPosition += batchSize
+	// not shuffled data sets are in their order, so they don't have to be copied
+	if !trainer.batch.Shuffle {
+		return trainer.trainDataSets[start:trainer.epochPosition], true
+	}
+	batch := make(network.DataSets, batchSize)
+	for i := range batch {
+		batch[i] = trainer.trainDataSets[trainer.epochOrder[start+i]]
+	}
+	return batch, true
+}
+
+// returns the next batch, starting a new epoch if the current one has ended
+func (trainer *Trainer) nextEvolutionBatch(batchSize int) network.DataSets {
+	if trainer.epochEnded(batchSize) {
+		trainer.startEpoch()
+	}
+	batch, _ := trainer.nextBatch(batchSize)
+	return batch
+}
+
+// returns whether there are no more batches in the current epoch
+func (trainer *Trainer) epochEnded(batchSize int) bool {
+	remaining := len(trainer.epochOrder) - trainer.epochPosition
+	return remaining == 0 || (trainer.batch.DropLast && remaining < batchSize)
+}
+
+// returns whether the order contains every index from 0 to its length once
+func isPermutation(order []int) bool {
+	used := make([]bool, len(order))
+	for _, index := range order {
+		if index < 0 || index >= len(order) || used[index] {
+			return false
+		}
+		used[index] = true
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package training
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// EventType tells at which point of the training an event has happened
+type EventType int
+
+const (
+	GenerationStart EventType = iota
+	GenerationEnd
+	// sent after the generation which has used the last batch of the epoch
+	EpochEnd
+	TrainEnd
+)
+
+var eventTypeNames = map[EventType]string{
+	GenerationStart: "generationStart",
+	GenerationEnd:   "generationEnd",
+	EpochEnd:        "epochEnd",
+	TrainEnd:        "trainEnd",
+}
+
+func (eventType EventType) String() string {
+	return eventTypeNames[eventType]
+}
+
+// The state of the training sent to callbacks and the event channel
+type TrainingEvent struct {
+	Type      EventType
+	Algorithm Algorithm
+	// the number of all training iterations done by the trainer, including the just ended one
+	Generation int
+	// the number of all epochs ended by the trainer
+	Epoch int
+	// The lowest, average and highest cost of the networks. After a generation of the evolution they are
+	// measured on its batch and after a generation of the back propagation all of them are the trained network's
+	// cost on the training data. At the end of the training they are measured like by Best. They are 0 at a generation's start
+	BestCost    float64
+	AverageCost float64
+	WorstCost   float64
+	// the result of the validation after the generation, nil if there is no validation data
+	Validation *ValidationResult
+	// how much the children of the evolution's generation were mutated, nil for the back propagation
+	Mutation *MutationStatistics
+	// the duration of the generation or of the whole training at its end
+	Duration time.Duration
+	// the time since the start of the training
+	Elapsed time.Duration
+	// whether the training has been stopped early, set only at its end
+	StoppedEarly bool
+}
+
+// Callback is notified about the progress of the training. Returning an error stops the training
+// and Train returns it, except for ErrStopTraining which stops the training without an error
+type Callback interface {
+	OnGenerationStart(event TrainingEvent) error
+	OnGenerationEnd(event TrainingEvent) error
+	OnEpochEnd(event TrainingEvent) error
+	OnTrainEnd(event TrainingEvent) error
+}
+
+// returned by a callback stops the training like the early stopping does
+var ErrStopTraining = errors.New("the training has been stopped by a callback")
+
+// Callback which calls only the functions which were given
+type CallbackFuncs struct {
+	GenerationStart func(event TrainingEvent) error
+	GenerationEnd   func(event TrainingEvent) error
+	EpochEnd        func(event TrainingEvent) error
+	TrainEnd        func(event TrainingEvent) error
+}
+
+func (callback CallbackFuncs) OnGenerationStart(event TrainingEvent) error {
+	return callFunc(callback.GenerationStart, event)
+}
+
+func (callback CallbackFuncs) OnGenerationEnd(event TrainingEvent) error {
+	return callFunc(callback.GenerationEnd, event)
+}
+
+func (callback CallbackFuncs) OnEpochEnd(event TrainingEvent) error {
+	return callFunc(callback.EpochEnd, event)
+}
+
+func (callback CallbackFuncs) OnTrainEnd(event TrainingEvent) error {
+	return callFunc(callback.TrainEnd, event)
+}
+
+func callFunc(function func(event TrainingEvent) error, event TrainingEvent) error {
+	if function == nil {
+		return nil
+	}
+	return function(event)
+}
+
+// Adds the callback notified about the progress of every next training
+func (trainer *Trainer) AddCallback(callback Callback) {
+	trainer.callbacks = append(trainer.callbacks, callback)
+}
+
+// Sets the channel to which all training events are sent. The training waits until every event is received
+// or its context is done, so the channel has to be read or buffered. It isn't closed by the trainer. Nil turns the events off
+func (trainer *Trainer) SetEventChannel(events chan<- TrainingEvent) {
+	trainer.events = events
+}
+
+// returns whether anything is notified about the training, so the events have to be created
+func (trainer *Trainer) hasListeners() bool {
+	return trainer.events != nil || len(trainer.callbacks) != 0
+}
+
+// Sends the event to the channel and all callbacks. It stops at the first callback's error.
+// The event isn't sent to the channel if the context is done before it is received
+func (trainer *Trainer) notify(ctx context.Context, event TrainingEvent) error {
+	if !trainer.hasListeners() {
+		return nil
+	}
+	if trainer.events != nil {
+		select {
+		case trainer.events <- event:
+		case <-ctx.Done():
+		}
+	}
+	for _, callback := range trainer.callbacks {
+		var err error
+		switch event.Type {
+		case GenerationStart:
+			err = callback.OnGenerationStart(event)
+		case GenerationEnd:
+			err = callback.OnGenerationEnd(event)
+		case EpochEnd:
+			err = callback.OnEpochEnd(event)
+		case TrainEnd:
+			err = callback.OnTrainEnd(event)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the event with the trainer's counters
+func (trainer *Trainer) newEvent(eventType EventType, algorithm Algorithm, trainingStart time.Time) TrainingEvent {
+	return TrainingEvent{
+		Type:       eventType,
+		Algorithm:  algorithm,
+		Generation: trainer.generation,
+		Epoch:      trainer.epoch,
+		Elapsed:    time.Since(trainingStart),
+	}
+}
+
+// Returns the event sent after the generation. The costs are calculated only if anything is notified
+func (trainer *Trainer) newGenerationEndEvent(algorithm Algorithm, trainingStart, generationStart time.Time) TrainingEvent {
+	event := trainer.newEvent(GenerationEnd, algorithm, trainingStart)
+	event.Duration = time.Since(generationStart)
+	if !trainer.hasListeners() {
+		return event
+	}
+	if algorithm == BackPropagation {
+		cost := trainer.networks[0].GetAverageCost(trainer.trainDataSets, trainer.loss)
+		event.BestCost, event.AverageCost, event.WorstCost = cost, cost, cost
+	} else {
+		event.BestCost, event.AverageCost, event.WorstCost = trainer.getCostsSummary()
+		statistics := trainer.mutationHistory[len(trainer.mutationHistory)-1]
+		event.Mutation = &statistics
+	}
+	if len(trainer.validationDataSets) != 0 {
+		result := trainer.validationHistory[len(trainer.validationHistory)-1]
+		event.Validation = &result
+	}
+	return event
+}
+
+// returns the lowest, average and highest cost of all networks
+func (trainer *Trainer) getCostsSummary() (best, average, worst float64) {
+	best, worst = math.Inf(1), math.Inf(-1)
+	for i := range trainer.networks {
+		cost := trainer.networks[i].GetCost()
+		best = math.Min(best, cost)
+		worst = math.Max(worst, cost)
+		average += cost / float64(len(trainer.networks))
+	}
+	return best, average, worst
+}
@@ -0,0 +1,231 @@
+package training
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// the version of the checkpoint's schema. It has to be increased with every incompatible change
+const checkpointVersion = 1
+
+// The JSON representation of the whole trainer's state.
+// Networks' costs and training data aren't saved as they are given and calculated again by Train
+type checkpoint struct {
+	Version          int                  `json:"version"`
+	NumberOfNetworks int                  `json:"numberOfNetworks"`
+	Generation       int                  `json:"generation"`
+	Epoch            int                  `json:"epoch,omitempty"`
+	EpochOrder       []int                `json:"epochOrder,omitempty"`
+	EpochPosition    int                  `json:"epochPosition,omitempty"`
+	RandomState      uint64               `json:"randomState"`
+	Loss             LossConfig           `json:"loss"`
+	Optimizer        OptimizerConfig      `json:"optimizer"`
+	OptimizerStep    int                  `json:"optimizerStep,omitempty"`
+	OptimizerState   map[string][]float64 `json:"optimizerState,omitempty"`
+	Evolution        *EvolutionConfig     `json:"evolution,omitempty"`
+	Selection        *SelectionConfig     `json:"selection,omitempty"`
+	Crossover        *CrossoverConfig     `json:"crossover,omitempty"`
+	Mutation         *MutationConfig      `json:"mutation,omitempty"`
+	Batch            *BatchConfig         `json:"batch,omitempty"`
+	EarlyStopping    *EarlyStoppingConfig `json:"earlyStopping,omitempty"`
+	HeldOut          []bool               `json:"heldOut,omitempty"`
+	Networks         []network.Network    `json:"networks"`
+}
+
+// Writes the whole trainer's state as JSON: the population, the generation counter, the position in the current epoch,
+// the state of random numbers' source, the loss, the optimizer with its state and the data sets held out for the validation.
+// Only the optimizers, losses, selectors, crossovers and mutators defined by this module can be saved
+func (trainer *Trainer) Checkpoint(w io.Writer) error {
+	if !trainer.Initialized {
+		return errors.New("the trainer isn't initialized")
+	}
+	loss, err := NewLossConfig(trainer.loss)
+	if err != nil {
+		return err
+	}
+	optimizer, err := NewOptimizerConfig(trainer.optimizer)
+	if err != nil {
+		return err
+	}
+	optimizerStep, optimizerState := getOptimizerState(trainer.optimizer)
+	var selection *SelectionConfig
+	if trainer.selector != nil {
+		config, err := NewSelectionConfig(trainer.selector)
+		if err != nil {
+			return err
+		}
+		selection = &config
+	}
+	var crossover *CrossoverConfig
+	if trainer.crossover != nil {
+		config, err := NewCrossoverConfig(trainer.crossover)
+		if err != nil {
+			return err
+		}
+		crossover = &config
+	}
+	var mutation *MutationConfig
+	if trainer.mutator != nil {
+		config, err := NewMutationConfig(trainer.mutator)
+		if err != nil {
+			return err
+		}
+		mutation = &config
+	}
+
+	myCheckpoint := checkpoint{
+		Version:          checkpointVersion,
+		NumberOfNetworks: trainer.numberOfNetworks,
+		Generation:       trainer.generation,
+		Epoch:            trainer.epoch,
+		EpochOrder:       trainer.epochOrder,
+		EpochPosition:    trainer.epochPosition,
+		RandomState:      trainer.randomSource.state,
+		Loss:             loss,
+		Optimizer:        optimizer,
+		OptimizerStep:    optimizerStep,
+		OptimizerState:   optimizerState,
+		Evolution:        &trainer.evolution,
+		Selection:        selection,
+		Crossover:        crossover,
+		Mutation:         mutation,
+		Batch:            &trainer.batch,
+		EarlyStopping:    trainer.earlyStopping,
+		HeldOut:          trainer.heldOut,
+		Networks:         trainer.networks,
+	}
+	return json.NewEncoder(w).Encode(myCheckpoint)
+}
+
+// Returns the trainer restored from the checkpoint written by Checkpoint.
+// It continues the training exactly where the saved trainer has stopped
+func ResumeTrainer(r io.Reader) (Trainer, error) {
+	var myCheckpoint checkpoint
+	if err := json.NewDecoder(r).Decode(&myCheckpoint); err != nil {
+		return Trainer{}, err
+	}
+	if myCheckpoint.Version != checkpointVersion {
+		return Trainer{}, fmt.Errorf("unsupported checkpoint version: %d", myCheckpoint.Version)
+	}
+	if myCheckpoint.NumberOfNetworks <= 0 || myCheckpoint.Generation < 0 || myCheckpoint.Epoch < 0 {
+		return Trainer{}, errors.New("incorrect number of networks, generation or epoch")
+	}
+	if myCheckpoint.EpochPosition < 0 || myCheckpoint.EpochPosition > len(myCheckpoint.EpochOrder) || !isPermutation(myCheckpoint.EpochOrder) {
+		return Trainer{}, errors.New("incorrect order or position of the current epoch")
+	}
+	if len(myCheckpoint.Networks) == 0 {
+		return Trainer{}, errors.New("the checkpoint has no networks")
+	}
+	if myCheckpoint.NumberOfNetworks > len(myCheckpoint.Networks) {
+		return Trainer{}, errors.New("number of networks is bigger than number of saved networks")
+	}
+	for i := range myCheckpoint.Networks {
+		if !myCheckpoint.Networks[i].HasSameArchitecture(&myCheckpoint.Networks[0]) {
+			return Trainer{}, errors.New("all networks have to have the same structure, output labels and activations")
+		}
+	}
+	loss, err := myCheckpoint.Loss.NewLoss()
+	if err != nil {
+		return Trainer{}, err
+	}
+	optimizer, err := myCheckpoint.Optimizer.NewOptimizer()
+	if err != nil {
+		return Trainer{}, err
+	}
+	setOptimizerState(optimizer, myCheckpoint.OptimizerStep, myCheckpoint.OptimizerState)
+	evolution := DefaultEvolutionConfig()
+	if myCheckpoint.Evolution != nil {
+		evolution = *myCheckpoint.Evolution
+	}
+	if err := evolution.Validate(); err != nil {
+		return Trainer{}, err
+	}
+	var selector Selector
+	if myCheckpoint.Selection != nil {
+		if selector, err = myCheckpoint.Selection.NewSelector(); err != nil {
+			return Trainer{}, err
+		}
+	}
+	var crossover Crossover
+	if myCheckpoint.Crossover != nil {
+		if crossover, err = myCheckpoint.Crossover.NewCrossover(); err != nil {
+			return Trainer{}, err
+		}
+	}
+	var mutator Mutator
+	if myCheckpoint.Mutation != nil {
+		if mutator, err = myCheckpoint.Mutation.NewMutator(); err != nil {
+			return Trainer{}, err
+		}
+	}
+	var batch BatchConfig
+	if myCheckpoint.Batch != nil {
+		batch = *myCheckpoint.Batch
+	}
+	if err := batch.Validate(); err != nil {
+		return Trainer{}, err
+	}
+	if myCheckpoint.EarlyStopping != nil {
+		if err := myCheckpoint.EarlyStopping.Validate(); err != nil {
+			return Trainer{}, err
+		}
+	}
+
+	var trainer Trainer
+	trainer.numberOfNetworks = myCheckpoint.NumberOfNetworks
+	trainer.generation = myCheckpoint.Generation
+	trainer.epoch = myCheckpoint.Epoch
+	trainer.epochOrder = myCheckpoint.EpochOrder
+	trainer.epochPosition = myCheckpoint.EpochPosition
+	trainer.networks = myCheckpoint.Networks
+	trainer.loss = loss
+	trainer.optimizer = optimizer
+	trainer.evolution = evolution
+	trainer.selector = selector
+	trainer.crossover = crossover
+	trainer.mutator = mutator
+	trainer.batch = batch
+	trainer.earlyStopping = myCheckpoint.EarlyStopping
+	trainer.heldOut = myCheckpoint.HeldOut
+	trainer.setRandomSource(&randomSource{state: myCheckpoint.RandomState})
+	trainer.Initialized = true
+	return trainer, nil
+}
+
+// returns the number of done steps and the state of every parameter kept by the optimizer
+func getOptimizerState(optimizer Optimizer) (int, map[string][]float64) {
+	switch myOptimizer := optimizer.(type) {
+	case *Momentum:
+		return 0, map[string][]float64{"velocities": myOptimizer.velocities}
+	case *RMSProp:
+		return 0, map[string][]float64{"squaredAverages": myOptimizer.squaredAverages}
+	case *Adagrad:
+		return 0, map[string][]float64{"squaredSums": myOptimizer.squaredSums}
+	case *Adam:
+		return myOptimizer.step, map[string][]float64{
+			"firstMoments":  myOptimizer.firstMoments,
+			"secondMoments": myOptimizer.secondMoments,
+		}
+	}
+	return 0, nil
+}
+
+// restores the state returned by getOptimizerState
+func setOptimizerState(optimizer Optimizer, step int, state map[string][]float64) {
+	switch myOptimizer := optimizer.(type) {
+	case *Momentum:
+		myOptimizer.velocities = state["velocities"]
+	case *RMSProp:
+		myOptimizer.squaredAverages = state["squaredAverages"]
+	case *Adagrad:
+		myOptimizer.squaredSums = state["squaredSums"]
+	case *Adam:
+		myOptimizer.firstMoments = state["firstMoments"]
+		myOptimizer.secondMoments = state["secondMoments"]
+		myOptimizer.step = step
+	}
+}
@@ -0,0 +1,144 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// Describes a loss so it can be read from or written to a configuration file
+type LossConfig struct {
+	// meanSquaredError, binaryCrossEntropy, categoricalCrossEntropy, hinge or huber
+	Type string `json:"type"`
+	// used only by huber
+	Delta float64 `json:"delta,omitempty"`
+}
+
+// returns the description of the given loss. Only the losses defined by this module can be described
+func NewLossConfig(loss network.Loss) (LossConfig, error) {
+	switch myLoss := loss.(type) {
+	case network.MeanSquaredError:
+		return LossConfig{Type: "meanSquaredError"}, nil
+	case network.BinaryCrossEntropy:
+		return LossConfig{Type: "binaryCrossEntropy"}, nil
+	case network.CategoricalCrossEntropy:
+		return LossConfig{Type: "categoricalCrossEntropy"}, nil
+	case network.Hinge:
+		return LossConfig{Type: "hinge"}, nil
+	case network.Huber:
+		return LossConfig{Type: "huber", Delta: myLoss.Delta}, nil
+	}
+	return LossConfig{}, fmt.Errorf("loss %T can't be described", loss)
+}
+
+// returns the described loss
+func (config LossConfig) NewLoss() (network.Loss, error) {
+	switch config.Type {
+	case "meanSquaredError":
+		return network.MeanSquaredError{}, nil
+	case "binaryCrossEntropy":
+		return network.BinaryCrossEntropy{}, nil
+	case "categoricalCrossEntropy":
+		return network.CategoricalCrossEntropy{}, nil
+	case "hinge":
+		return network.Hinge{}, nil
+	case "huber":
+		if config.Delta <= 0 {
+			return nil, errors.New("huber's delta has to be bigger than 0")
+		}
+		return network.Huber{Delta: config.Delta}, nil
+	}
+	return nil, errors.New("unknown loss: " + config.Type)
+}
+
+// Describes an optimizer so it can be read from or written to a configuration file.
+// Its state isn't a part of the description
+type OptimizerConfig struct {
+	// sgd, momentum, rmsProp, adagrad or adam
+	Type         string  `json:"type"`
+	LearningRate float64 `json:"learningRate"`
+	// used only by momentum
+	Momentum float64 `json:"momentum,omitempty"`
+	Nesterov bool    `json:"nesterov,omitempty"`
+	// used only by rmsProp
+	Decay float64 `json:"decay,omitempty"`
+	// used only by adam
+	Beta1 float64 `json:"beta1,omitempty"`
+	Beta2 float64 `json:"beta2,omitempty"`
+}
+
+// returns the description of the given optimizer. Only the optimizers defined by this module can be described
+func NewOptimizerConfig(optimizer Optimizer) (OptimizerConfig, error) {
+	switch myOptimizer := optimizer.(type) {
+	case *SGD:
+		return OptimizerConfig{Type: "sgd", LearningRate: myOptimizer.LearningRate}, nil
+	case *Momentum:
+		return OptimizerConfig{Type: "momentum", LearningRate: myOptimizer.LearningRate, Momentum: myOptimizer.Momentum, Nesterov: myOptimizer.Nesterov}, nil
+	case *RMSProp:
+		return OptimizerConfig{Type: "rmsProp", LearningRate: myOptimizer.LearningRate, Decay: myOptimizer.Decay}, nil
+	case *Adagrad:
+		return OptimizerConfig{Type: "adagrad", LearningRate: myOptimizer.LearningRate}, nil
+	case *Adam:
+		return OptimizerConfig{Type: "adam", LearningRate: myOptimizer.LearningRate, Beta1: myOptimizer.Beta1, Beta2: myOptimizer.Beta2}, nil
+	}
+	return OptimizerConfig{}, fmt.Errorf("optimizer %T can't be described", optimizer)
+}
+
+// returns the described optimizer with an empty state
+func (config OptimizerConfig) NewOptimizer() (Optimizer, error) {
+	if config.LearningRate <= 0 {
+		return nil, errors.New("learning rate has to be bigger than 0")
+	}
+	switch config.Type {
+	case "sgd":
+		return NewSGD(config.LearningRate), nil
+	case "momentum":
+		if config.Momentum < 0 || config.Momentum >= 1 {
+			return nil, errors.New("momentum has to be between 0 and 1")
+		}
+		return NewMomentum(config.LearningRate, config.Momentum, config.Nesterov), nil
+	case "rmsProp":
+		if config.Decay < 0 || config.Decay >= 1 {
+			return nil, errors.New("decay has to be between 0 and 1")
+		}
+		return NewRMSProp(config.LearningRate, config.Decay), nil
+	case "adagrad":
+		return NewAdagrad(config.LearningRate), nil
+	case "adam":
+		if config.Beta1 < 0 || config.Beta1 >= 1 || config.Beta2 < 0 || config.Beta2 >= 1 {
+			return nil, errors.New("betas have to be between 0 and 1")
+		}
+		return NewAdam(config.LearningRate, config.Beta1, config.Beta2), nil
+	}
+	return nil, errors.New("unknown optimizer: " + config.Type)
+}
+
+var algorithmNames = map[Algorithm]string{
+	Evolution:       "evolution",
+	BackPropagation: "backPropagation",
+}
+
+func (algorithm Algorithm) String() string {
+	if name, ok := algorithmNames[algorithm]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (algorithm Algorithm) MarshalText() ([]byte, error) {
+	if _, ok := algorithmNames[algorithm]; !ok {
+		return nil, errors.New("unknown training algorithm")
+	}
+	return []byte(algorithm.String()), nil
+}
+
+func (algorithm *Algorithm) UnmarshalText(text []byte) error {
+	for myAlgorithm, name := range algorithmNames {
+		if name == string(text) {
+			*algorithm = myAlgorithm
+			return nil
+		}
+	}
+	return errors.New("unknown training algorithm: " + string(text))
+}
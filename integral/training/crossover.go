@@ -0,0 +1,242 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// Crossover creates the genes of a child from the genes of its two parents.
+// The genes are networks' raw parameters, so all of them have the same length and layout
+type Crossover interface {
+	// Writes the child's genes made from the first and the second parent's genes
+	Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64)
+}
+
+// Tells which genes belong together, so they can be taken from the same parent
+type GenomeLayout struct {
+	// indexes of genes of every layer except the input one, which has no genes
+	Layers [][]int
+	// indexes of genes of every node except the input ones - its bias and weights of connections to it
+	Nodes [][]int
+}
+
+// returns the layout of the network's genes
+func newGenomeLayout(net *network.Network) GenomeLayout {
+	return GenomeLayout{
+		Layers: net.GetLayerParameterIndexes(),
+		Nodes:  net.GetNodeParameterIndexes(),
+	}
+}
+
+// returns the layout of the trained networks' genes, it is built only once
+func (trainer *Trainer) getGenomeLayout() GenomeLayout {
+	if trainer.genomeLayout == nil {
+		layout := newGenomeLayout(&trainer.networks[0])
+		trainer.genomeLayout = &layout
+	}
+	return *trainer.genomeLayout
+}
+
+// Every gene is taken from the first parent with the chance of Bias and from the second one otherwise.
+// Bias is in (0, 1) as otherwise one of the parents would be copied
+type UniformCrossover struct {
+	Bias float64
+}
+
+func (crossover UniformCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	for i := range child {
+		if random.Float64() < crossover.Bias {
+			child[i] = first[i]
+		} else {
+			child[i] = second[i]
+		}
+	}
+}
+
+// Genes before a random point are taken from the first parent and the rest from the second one
+type SinglePointCrossover struct{}
+
+func (SinglePointCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	if len(child) < 2 {
+		copy(child, first)
+		return
+	}
+	point := 1 + random.Intn(len(child)-1)
+	copy(child[:point], first[:point])
+	copy(child[point:], second[point:])
+}
+
+// Genes between two random points are taken from the second parent and the rest from the first one
+type TwoPointCrossover struct{}
+
+func (TwoPointCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	start, end := random.Intn(len(child)+1), random.Intn(len(child)+1)
+	if start > end {
+		start, end = end, start
+	}
+	copy(child, first)
+	copy(child[start:end], second[start:end])
+}
+
+// Every layer is taken as a whole from a parent chosen at random
+type LayerCrossover struct{}
+
+func (LayerCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	crossBlocks(random, layout.Layers, first, second, child)
+}
+
+// Every node's bias and weights of connections to it are taken together from a parent chosen at random
+type NodeCrossover struct{}
+
+func (NodeCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	crossBlocks(random, layout.Nodes, first, second, child)
+}
+
+// Every gene is the weighted average of the parents' genes: Weight * first + (1 - Weight) * second.
+// Weight is in (0, 1) as otherwise one of the parents would be copied
+type ArithmeticCrossover struct {
+	Weight float64
+}
+
+func (crossover ArithmeticCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	for i := range child {
+		child[i] = crossover.Weight*first[i] + (1-crossover.Weight)*second[i]
+	}
+}
+
+// BLX-α. Every gene is drawn from the range between the parents' genes extended
+// on both sides by Alpha times its length. Alpha can't be negative
+type BlendCrossover struct {
+	Alpha float64
+}
+
+func (crossover BlendCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	for i := range child {
+		low, high := math.Min(first[i], second[i]), math.Max(first[i], second[i])
+		extension := crossover.Alpha * (high - low)
+		child[i] = low - extension + random.Float64()*(high-low+2*extension)
+	}
+}
+
+// Simulated binary crossover (SBX). Genes are spread around the parents' ones like in the single-point crossover
+// of binary numbers. A bigger distribution index Eta keeps the child closer to its parents. Eta can't be negative
+type SimulatedBinaryCrossover struct {
+	Eta float64
+}
+
+func (crossover SimulatedBinaryCrossover) Crossover(random *rand.Rand, layout GenomeLayout, first, second, child []float64) {
+	for i := range child {
+		u := random.Float64()
+		var beta float64
+		if u <= 0.5 {
+			beta = math.Pow(2*u, 1/(crossover.Eta+1))
+		} else {
+			beta = math.Pow(1/(2*(1-u)), 1/(crossover.Eta+1))
+		}
+		// one of the two SBX children is chosen at random
+		if random.Intn(2) == 0 {
+			beta = -beta
+		}
+		child[i] = 0.5 * ((1+beta)*first[i] + (1-beta)*second[i])
+	}
+}
+
+// takes every block of genes from a parent chosen at random
+func crossBlocks(random *rand.Rand, blocks [][]int, first, second, child []float64) {
+	for _, block := range blocks {
+		parent := first
+		if random.Intn(2) == 1 {
+			parent = second
+		}
+		for _, index := range block {
+			child[index] = parent[index]
+		}
+	}
+}
+
+// checks if hyperparameters of the crossovers defined by this module are in their ranges
+func ValidateCrossover(crossover Crossover) error {
+	config, err := NewCrossoverConfig(crossover)
+	if err != nil {
+		// other crossovers can't be checked
+		return nil
+	}
+	_, err = config.NewCrossover()
+	return err
+}
+
+// Describes a crossover so it can be read from or written to a configuration file
+type CrossoverConfig struct {
+	// uniform, singlePoint, twoPoint, layer, node, arithmetic, blend or simulatedBinary
+	Type string `json:"type"`
+	// used only by uniform
+	Bias float64 `json:"bias,omitempty"`
+	// used only by arithmetic
+	Weight float64 `json:"weight,omitempty"`
+	// used only by blend
+	Alpha float64 `json:"alpha,omitempty"`
+	// used only by simulatedBinary
+	Eta float64 `json:"eta,omitempty"`
+}
+
+// returns the description of the given crossover. Only the crossovers defined by this module can be described
+func NewCrossoverConfig(crossover Crossover) (CrossoverConfig, error) {
+	switch myCrossover := crossover.(type) {
+	case UniformCrossover:
+		return CrossoverConfig{Type: "uniform", Bias: myCrossover.Bias}, nil
+	case SinglePointCrossover:
+		return CrossoverConfig{Type: "singlePoint"}, nil
+	case TwoPointCrossover:
+		return CrossoverConfig{Type: "twoPoint"}, nil
+	case LayerCrossover:
+		return CrossoverConfig{Type: "layer"}, nil
+	case NodeCrossover:
+		return CrossoverConfig{Type: "node"}, nil
+	case ArithmeticCrossover:
+		return CrossoverConfig{Type: "arithmetic", Weight: myCrossover.Weight}, nil
+	case BlendCrossover:
+		return CrossoverConfig{Type: "blend", Alpha: myCrossover.Alpha}, nil
+	case SimulatedBinaryCrossover:
+		return CrossoverConfig{Type: "simulatedBinary", Eta: myCrossover.Eta}, nil
+	}
+	return CrossoverConfig{}, fmt.Errorf("crossover %T can't be described", crossover)
+}
+
+// returns the described crossover
+func (config CrossoverConfig) NewCrossover() (Crossover, error) {
+	switch config.Type {
+	case "uniform":
+		if config.Bias <= 0 || config.Bias >= 1 {
+			return nil, errors.New("uniform crossover's bias has to be bigger than 0 and smaller than 1")
+		}
+		return UniformCrossover{Bias: config.Bias}, nil
+	case "singlePoint":
+		return SinglePointCrossover{}, nil
+	case "twoPoint":
+		return TwoPointCrossover{}, nil
+	case "layer":
+		return LayerCrossover{}, nil
+	case "node":
+		return NodeCrossover{}, nil
+	case "arithmetic":
+		if config.Weight <= 0 || config.Weight >= 1 {
+			return nil, errors.New("arithmetic crossover's weight has to be bigger than 0 and smaller than 1")
+		}
+		return ArithmeticCrossover{Weight: config.Weight}, nil
+	case "blend":
+		if config.Alpha < 0 {
+			return nil, errors.New("blend crossover's alpha can't be negative")
+		}
+		return BlendCrossover{Alpha: config.Alpha}, nil
+	case "simulatedBinary":
+		if config.Eta < 0 {
+			return nil, errors.New("simulated binary crossover's eta can't be negative")
+		}
+		return SimulatedBinaryCrossover{Eta: config.Eta}, nil
+	}
+	return nil, errors.New("unknown crossover: " + config.Type)
+}
@@ -1,35 +1,75 @@
 package training
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
-	"math"
 	"math/rand"
 
 	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
 )
 
-// a number determining the range of random mutations
-const strengthOfEvolution = 10
+// Hyperparameters of the evolution algorithm
+type EvolutionConfig struct {
+	// a number determining the range of random mutations, used only if no mutator is set
+	StrengthOfEvolution float64 `json:"strengthOfEvolution"`
+	// determines how much children are created in comparison to number of parents (0, inf)
+	// heavy performance impact
+	PercentageOfChildrenToParents float64 `json:"percentageOfChildrenToParents"`
+	// determines wheter the network will favour the best networks while mating, used only if no selector is set
+	FavourBestNetworksWhileMating bool `json:"favourBestNetworksWhileMating"`
+	// determines what weight will the network get -> every next gets multiplied by this number (0,1)
+	// it is the ratio of the geometric selection used if the best networks are favoured
+	MaxNetworksSurvivorsWeight float64 `json:"maxNetworksSurvivorsWeight"`
+}
 
-// determines how much children are created in comparison to number of parents (0, inf)
-// heavy performance impact
-const percentageOfChildrenToParents = 0.5
+// returns the configuration used by the trainer if no other is given
+func DefaultEvolutionConfig() EvolutionConfig {
+	return EvolutionConfig{
+		StrengthOfEvolution:           10,
+		PercentageOfChildrenToParents: 0.5,
+		FavourBestNetworksWhileMating: true,
+		MaxNetworksSurvivorsWeight:    0.8,
+	}
+}
 
-// determines wheter the network will favour the best networks while mating
-const favourBestNetworksWhileMating = true
+// Decodes the configuration on top of the default one, so the omitted hyperparameters keep their default values
+func (config *EvolutionConfig) UnmarshalJSON(data []byte) error {
+	// the plain type doesn't have this method, so decoding it doesn't call it again
+	type plainEvolutionConfig EvolutionConfig
+	myConfig := plainEvolutionConfig(DefaultEvolutionConfig())
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&myConfig); err != nil {
+		return err
+	}
+	*config = EvolutionConfig(myConfig)
+	return nil
+}
 
-// determines what weight will the network get -> every next gets multiplied by this number (0,1)
-const maxNetworksSurvivorsWeight = 0.8
+// checks if all hyperparameters are in their ranges
+func (config EvolutionConfig) Validate() error {
+	if config.StrengthOfEvolution < 0 {
+		return errors.New("strength of evolution can't be negative")
+	}
+	if config.PercentageOfChildrenToParents <= 0 {
+		return errors.New("percentage of children to parents has to be bigger than 0")
+	}
+	if config.MaxNetworksSurvivorsWeight <= 0 || config.MaxNetworksSurvivorsWeight >= 1 {
+		return errors.New("max networks survivors weight has to be between 0 and 1")
+	}
+	return nil
+}
 
-func (trainer *Trainer) evolutionTraining() error {
-	calculateAverageCosts(&trainer.networks, trainer.trainDataSets)
-	if favourBestNetworksWhileMating {
-		err := trainer.createNewFavouredGeneration(getSortedNetworks(&trainer.networks))
-		if err != nil {
-			return err
-		}
-	} else {
-		trainer.createNewRandomGeneration()
+// Creates a new generation of networks which costs are measured on the given batch of training data.
+// If the context is done the networks stay the same and the context's error is returned
+func (trainer *Trainer) evolutionTraining(ctx context.Context, batch network.DataSets) error {
+	if err := calculateAverageCostsContext(ctx, &trainer.networks, batch, trainer.loss); err != nil {
+		return err
+	}
+	if err := trainer.createNewGeneration(ctx, batch); err != nil {
+		return err
 	}
 	trainer.killWorstNetworks()
 
@@ -45,117 +85,62 @@ func (trainer *Trainer) killWorstNetworks() {
 	trainer.networks = newNetworks
 }
 
-// Creates new child networks from the parents and appends them to the trainer
-// Parents are chosen using weights -> the parent with the lowest cost has the advantage
-func (trainer *Trainer) createNewFavouredGeneration(sortedNet []*network.Network) error {
-	numberOfChildren := int(float64(len(trainer.networks)) * percentageOfChildrenToParents)
-	children := make([]network.Network, 0, numberOfChildren)
-	maxWeight := getMaxSurvivorWeight(len(trainer.networks))
-
-	for i := 0; i < numberOfChildren; i++ {
-		// loops until it finds two diffrent survivors
-		first, err := getRandomWeightedIndex(len(trainer.networks), maxWeight)
-		if err != nil {
-			return err
-		}
-		second := -1
-		for first != second {
-			second, err = getRandomWeightedIndex(len(trainer.networks), maxWeight)
-			if err != nil {
-				return err
-			}
-		}
-		children = append(children, createChildFromParents(*sortedNet[first], *sortedNet[second]))
+// Creates new child networks from the parents, measures their costs on the batch and appends them to the trainer.
+// Parents are chosen by the trainer's selector and every child has two different parents
+func (trainer *Trainer) createNewGeneration(ctx context.Context, batch network.DataSets) error {
+	numberOfChildren := int(float64(len(trainer.networks)) * trainer.evolution.PercentageOfChildrenToParents)
+	sortedNet := getSortedNetworks(&trainer.networks)
+	costs := make([]float64, len(sortedNet))
+	for i := range sortedNet {
+		costs[i] = sortedNet[i].GetCost()
 	}
-	calculateAverageCosts(&children, trainer.trainDataSets)
-	trainer.networks = append(trainer.networks, children...)
-	return nil
-}
+	selector := trainer.getSelector()
+	parents := selector.Select(trainer.random, costs, 2*numberOfChildren)
+	crossover, layout := trainer.GetCrossover(), trainer.getGenomeLayout()
+	mutator := trainer.GetMutator()
 
-// Creates new child networks from the parents and appends them to the trainer
-// Parents are chosen randomly
-func (trainer *Trainer) createNewRandomGeneration() {
-	numberOfChildren := int(float64(len(trainer.networks)) * percentageOfChildrenToParents)
 	children := make([]network.Network, 0, numberOfChildren)
+	counter := mutationCounter{statistics: MutationStatistics{Generation: trainer.generation + 1}}
 	for i := 0; i < numberOfChildren; i++ {
-		// loops until it finds two diffrent survivors
-		first := rand.Intn(len(trainer.networks))
-		second := -1
-		for first != second {
-			second = rand.Intn(len(trainer.networks))
-		}
-		children = append(children, createChildFromParents(trainer.networks[first], trainer.networks[second]))
+		first, second := parents[2*i], trainer.getSecondParent(selector, costs, parents[2*i], parents[2*i+1])
+		child := createChildFromParents(trainer.random, crossover, layout, *sortedNet[first], *sortedNet[second])
+		counter.add(&child, mutator.Mutate(trainer.random, trainer.generation, &child))
+		children = append(children, child)
 	}
-	calculateAverageCosts(&children, trainer.trainDataSets)
-	trainer.networks = append(trainer.networks, children...)
-}
-
-// returns an random(but using weights) number from [0,numberOfSurvivors)
-func getRandomWeightedIndex(numberOfNet int, maxWeight float64) (int, error) {
-	random := rand.Float64() * maxWeight
-	currentWeight := 0.0
-	for i := 0; i < numberOfNet; i++ {
-		currentWeight += math.Pow(maxNetworksSurvivorsWeight, float64(i))
-		if random <= currentWeight {
-			return i, nil
-		}
+	if err := calculateAverageCostsContext(ctx, &children, batch, trainer.loss); err != nil {
+		return err
 	}
-	return 0, errors.New("random survivor -> run out of survivors")
+	trainer.networks = append(trainer.networks, children...)
+	trainer.mutationHistory = append(trainer.mutationHistory, counter.finish())
+	return nil
 }
 
-// sets weights and biases of the given network with the values of parents
-// the value taken from parents is taken from them  in ratio50/50
-func createChildFromParents(first, second network.Network) network.Network {
-	nodesPerLayer := first.GetNetworkStructure()
-	var child network.Network
-	child.InitializeEmptyNetwork(nodesPerLayer, first.GetOutputLabels())
-	// iterating over layers
-	for i := 0; i < len(nodesPerLayer); i++ {
-		// iterating over all nodes in a layer
-		for j := 0; j < nodesPerLayer[i]; j++ {
-			setBias(&child, first, second, i, j)
+// the number of times the selector can choose the second parent again if it is the same as the first one
+const maxSecondParentSelections = 10
 
-			// iteratig over all node's weights
-			for k := 0; i != len(nodesPerLayer)-1 && k < nodesPerLayer[i+1]; k++ {
-				setWeight(&child, first, second, i, j, k)
-			}
-		}
+// Returns the second parent different from the first one. If the selector keeps choosing the first parent,
+// the second one is chosen at random from the rest. A single network is the only parent of its children
+func (trainer *Trainer) getSecondParent(selector Selector, costs []float64, first, second int) int {
+	if len(costs) == 1 {
+		return first
 	}
-	return child
-}
-
-// sets the given network's bias with randomly(50/50) selected parent's bias
-func setBias(net *network.Network, first, second network.Network, i, j int) {
-	var newBias float64
-	if rand.Intn(2) == 0 {
-		newBias = first.GetNodeBias(i, j)
-	} else {
-		newBias = second.GetNodeBias(i, j)
+	for i := 0; i < maxSecondParentSelections && second == first; i++ {
+		second = selector.Select(trainer.random, costs, 1)[0]
 	}
-	randomMutation := (rand.Float64() - 0.5) * 2 * strengthOfEvolution
-	newBias += randomMutation
-
-	net.SetNodeBias(i, j, newBias)
-}
-
-// sets the given network's weight with randomly(50/50) selected parent's weight
-func setWeight(net *network.Network, first, second network.Network, i, j, k int) {
-	var newWeight float64
-	if rand.Intn(2) == 0 {
-		newWeight = first.GetNodeWeight(i, j, k)
-	} else {
-		newWeight = second.GetNodeWeight(i, j, k)
+	if second == first {
+		second = trainer.random.Intn(len(costs) - 1)
+		if second >= first {
+			second++
+		}
 	}
-	randomMutation := (rand.Float64() - 0.5) * 2 * strengthOfEvolution
-	newWeight += randomMutation
-
-	net.SetNodeWeight(i, j, k, newWeight)
+	return second
 }
 
-// returns the maximal weight for a survivor for the amount of the survivors
-func getMaxSurvivorWeight(amountOfSurvivors int) (maxSurvivorWeight float64) {
-	for i := 0; i < amountOfSurvivors; i++ {
-		maxSurvivorWeight += math.Pow(maxNetworksSurvivorsWeight, float64(i))
-	}
-	return maxSurvivorWeight
+// Returns a network which weights and biases are mixed from the parents by the crossover.
+// It inherits the parents' self-adaptive mutation strength and has to be mutated
+func createChildFromParents(random *rand.Rand, crossover Crossover, layout GenomeLayout, first, second network.Network) network.Network {
+	child := first.CopyEmptyNetwork()
+	crossover.Crossover(random, layout, first.GetRawParameters(), second.GetRawParameters(), child.GetRawParameters())
+	child.SetMutationStrength(inheritMutationStrength(first.GetMutationStrength(), second.GetMutationStrength()))
+	return child
 }
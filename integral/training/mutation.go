@@ -0,0 +1,356 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// Mutator randomly changes the weights and biases of a child created by the crossover
+type Mutator interface {
+	// Mutates the child's raw parameters in place and returns how much they were changed. Generation is the number
+	// of training iterations done by the trainer before the current one, so the mutation can change during the training
+	Mutate(random *rand.Rand, generation int, child *network.Network) ChildMutation
+}
+
+// How much a single child's weights and biases were changed by the mutation
+type ChildMutation struct {
+	MutatedGenes int
+	// the sum and the highest of the absolute changes
+	SumOfChanges float64
+	MaxChange    float64
+}
+
+// counts the change of a single gene, a gene which hasn't changed isn't counted
+func (mutation *ChildMutation) Add(change float64) {
+	change = math.Abs(change)
+	if change == 0 {
+		return
+	}
+	mutation.MutatedGenes++
+	mutation.SumOfChanges += change
+	mutation.MaxChange = math.Max(mutation.MaxChange, change)
+}
+
+// Every gene is changed with the chance of Probability by a random number within ±Strength decreased by the Decay.
+// Probability is in (0, 1] and Strength can't be negative
+type UniformMutation struct {
+	Strength    float64
+	Probability float64
+	Decay       DecaySchedule
+}
+
+func (mutation UniformMutation) Mutate(random *rand.Rand, generation int, child *network.Network) ChildMutation {
+	var childMutation ChildMutation
+	strength := mutation.Strength * mutation.Decay.Factor(generation)
+	genes := child.GetRawParameters()
+	for i := range genes {
+		if shouldMutate(random, mutation.Probability) {
+			change := (random.Float64() - 0.5) * 2 * strength
+			genes[i] += change
+			childMutation.Add(change)
+		}
+	}
+	return childMutation
+}
+
+// Every gene is changed with the chance of Probability by a normally distributed number with the standard deviation
+// of Sigma decreased by the Decay. Small changes are much more common than with the uniform mutation,
+// so the learned structure is kept while rare big changes still happen. Probability is in (0, 1] and Sigma can't be negative
+type GaussianMutation struct {
+	Sigma       float64
+	Probability float64
+	Decay       DecaySchedule
+}
+
+func (mutation GaussianMutation) Mutate(random *rand.Rand, generation int, child *network.Network) ChildMutation {
+	var childMutation ChildMutation
+	sigma := mutation.Sigma * mutation.Decay.Factor(generation)
+	genes := child.GetRawParameters()
+	for i := range genes {
+		if shouldMutate(random, mutation.Probability) {
+			change := random.NormFloat64() * sigma
+			genes[i] += change
+			childMutation.Add(change)
+		}
+	}
+	return childMutation
+}
+
+// The Gaussian mutation which standard deviation σ is a part of every network, as in the evolution strategies.
+// A child inherits σ from its parents, multiplies it by exp(LearningRate * N(0, 1)) and mutates its genes with it,
+// so strengths which create good children survive with them
+type SelfAdaptiveMutation struct {
+	// σ of the networks which haven't got their own one yet, it has to be bigger than 0
+	InitialSigma float64
+	// τ, how fast σ changes. 0 means 1/sqrt(number of genes)
+	LearningRate float64
+	// the lowest σ, so the mutation can't vanish
+	MinSigma    float64
+	Probability float64
+}
+
+func (mutation SelfAdaptiveMutation) Mutate(random *rand.Rand, generation int, child *network.Network) ChildMutation {
+	genes := child.GetRawParameters()
+	sigma := child.GetMutationStrength()
+	if sigma == 0 {
+		sigma = mutation.InitialSigma
+	}
+	learningRate := mutation.LearningRate
+	if learningRate == 0 {
+		learningRate = 1 / math.Sqrt(float64(len(genes)))
+	}
+	sigma = math.Max(sigma*math.Exp(learningRate*random.NormFloat64()), mutation.MinSigma)
+	child.SetMutationStrength(sigma)
+
+	var childMutation ChildMutation
+	for i := range genes {
+		if shouldMutate(random, mutation.Probability) {
+			change := random.NormFloat64() * sigma
+			genes[i] += change
+			childMutation.Add(change)
+		}
+	}
+	return childMutation
+}
+
+// Every gene is replaced with the chance of Probability by a random number within ±Range,
+// so the mutated genes forget their values completely. Range has to be bigger than 0
+type ResetMutation struct {
+	Range       float64
+	Probability float64
+}
+
+func (mutation ResetMutation) Mutate(random *rand.Rand, generation int, child *network.Network) ChildMutation {
+	var childMutation ChildMutation
+	genes := child.GetRawParameters()
+	for i := range genes {
+		if shouldMutate(random, mutation.Probability) {
+			gene := (random.Float64() - 0.5) * 2 * mutation.Range
+			childMutation.Add(gene - genes[i])
+			genes[i] = gene
+		}
+	}
+	return childMutation
+}
+
+// returns whether a gene is mutated. A random number isn't used if every gene is mutated
+func shouldMutate(random *rand.Rand, probability float64) bool {
+	return probability >= 1 || random.Float64() < probability
+}
+
+// Decreases the mutation strength during the training. The zero value keeps it constant
+type DecaySchedule struct {
+	// exponential, step or linear. Empty keeps the strength constant
+	Type string `json:"type,omitempty"`
+	// exponential multiplies the strength by Rate every generation and step every Steps generations. Rate is in (0, 1)
+	Rate float64 `json:"rate,omitempty"`
+	// the number of generations between the step decays or after which the linear decay reaches Min
+	Steps int `json:"steps,omitempty"`
+	// the lowest fraction of the initial strength, in [0, 1)
+	Min float64 `json:"min,omitempty"`
+}
+
+// returns the fraction of the initial strength used in the given generation
+func (schedule DecaySchedule) Factor(generation int) float64 {
+	var factor float64
+	switch schedule.Type {
+	case "exponential":
+		factor = math.Pow(schedule.Rate, float64(generation))
+	case "step":
+		factor = math.Pow(schedule.Rate, float64(generation/schedule.Steps))
+	case "linear":
+		factor = 1 - (1-schedule.Min)*math.Min(float64(generation)/float64(schedule.Steps), 1)
+	default:
+		return 1
+	}
+	return math.Max(factor, schedule.Min)
+}
+
+// checks if all hyperparameters are in their ranges
+func (schedule DecaySchedule) Validate() error {
+	switch schedule.Type {
+	case "":
+		return nil
+	case "exponential", "step", "linear":
+	default:
+		return errors.New("unknown decay schedule: " + schedule.Type)
+	}
+	if schedule.Type != "linear" && (schedule.Rate <= 0 || schedule.Rate >= 1) {
+		return errors.New("decay rate has to be between 0 and 1")
+	}
+	if schedule.Type != "exponential" && schedule.Steps <= 0 {
+		return errors.New("decay steps have to be bigger than 0")
+	}
+	if schedule.Min < 0 || schedule.Min >= 1 {
+		return errors.New("decay min has to be between 0 and 1")
+	}
+	return nil
+}
+
+// How much the children of a single generation were mutated
+type MutationStatistics struct {
+	// the trainer's generation in which the children were created
+	Generation int
+	Children   int
+	// the fraction of children's weights and biases changed by the mutation
+	MutatedFraction float64
+	// the average and the highest absolute change of the changed weights and biases
+	AverageChange float64
+	MaxChange     float64
+	// the average self-adaptive mutation strength of the children, 0 if it isn't used
+	AverageStrength float64
+}
+
+// sums up the changes of the children's genes, they are turned into averages by finish
+type mutationCounter struct {
+	statistics    MutationStatistics
+	genes         int
+	mutatedGenes  int
+	sumOfChanges  float64
+	sumOfStrength float64
+}
+
+// adds the mutated child and how much it has been mutated
+func (counter *mutationCounter) add(child *network.Network, mutation ChildMutation) {
+	counter.statistics.Children++
+	counter.sumOfStrength += child.GetMutationStrength()
+	counter.genes += len(child.GetRawParameters())
+	counter.mutatedGenes += mutation.MutatedGenes
+	counter.sumOfChanges += mutation.SumOfChanges
+	counter.statistics.MaxChange = math.Max(counter.statistics.MaxChange, mutation.MaxChange)
+}
+
+// returns the statistics of all added children
+func (counter *mutationCounter) finish() MutationStatistics {
+	statistics := counter.statistics
+	if counter.genes != 0 {
+		statistics.MutatedFraction = float64(counter.mutatedGenes) / float64(counter.genes)
+	}
+	if counter.mutatedGenes != 0 {
+		statistics.AverageChange = counter.sumOfChanges / float64(counter.mutatedGenes)
+	}
+	if statistics.Children != 0 {
+		statistics.AverageStrength = counter.sumOfStrength / float64(statistics.Children)
+	}
+	return statistics
+}
+
+// returns the self-adaptive mutation strength of a child - the average of its parents' strengths which are used
+func inheritMutationStrength(first, second float64) float64 {
+	if first == 0 || second == 0 {
+		return first + second
+	}
+	return (first + second) / 2
+}
+
+// checks if hyperparameters of the mutators defined by this module are in their ranges
+func ValidateMutator(mutator Mutator) error {
+	config, err := NewMutationConfig(mutator)
+	if err != nil {
+		// other mutators can't be checked
+		return nil
+	}
+	_, err = config.NewMutator()
+	return err
+}
+
+// Describes a mutator so it can be read from or written to a configuration file
+type MutationConfig struct {
+	// uniform, gaussian, selfAdaptive or reset
+	Type string `json:"type"`
+	// the chance of mutating every weight and bias, in (0, 1]
+	Probability float64 `json:"probability"`
+	// used only by uniform
+	Strength float64 `json:"strength,omitempty"`
+	// used by gaussian and as the initial σ by selfAdaptive
+	Sigma float64 `json:"sigma,omitempty"`
+	// used only by selfAdaptive
+	LearningRate float64 `json:"learningRate,omitempty"`
+	MinSigma     float64 `json:"minSigma,omitempty"`
+	// used only by reset
+	Range float64 `json:"range,omitempty"`
+	// used by uniform and gaussian
+	Decay *DecaySchedule `json:"decay,omitempty"`
+}
+
+// returns the description of the given mutator. Only the mutators defined by this module can be described
+func NewMutationConfig(mutator Mutator) (MutationConfig, error) {
+	switch myMutator := mutator.(type) {
+	case UniformMutation:
+		return MutationConfig{Type: "uniform", Probability: myMutator.Probability, Strength: myMutator.Strength, Decay: getDecay(myMutator.Decay)}, nil
+	case GaussianMutation:
+		return MutationConfig{Type: "gaussian", Probability: myMutator.Probability, Sigma: myMutator.Sigma, Decay: getDecay(myMutator.Decay)}, nil
+	case SelfAdaptiveMutation:
+		return MutationConfig{
+			Type:         "selfAdaptive",
+			Probability:  myMutator.Probability,
+			Sigma:        myMutator.InitialSigma,
+			LearningRate: myMutator.LearningRate,
+			MinSigma:     myMutator.MinSigma,
+		}, nil
+	case ResetMutation:
+		return MutationConfig{Type: "reset", Probability: myMutator.Probability, Range: myMutator.Range}, nil
+	}
+	return MutationConfig{}, fmt.Errorf("mutator %T can't be described", mutator)
+}
+
+// returns the decay schedule to describe, nil if the strength is constant
+func getDecay(schedule DecaySchedule) *DecaySchedule {
+	if schedule == (DecaySchedule{}) {
+		return nil
+	}
+	return &schedule
+}
+
+// returns the described mutator
+func (config MutationConfig) NewMutator() (Mutator, error) {
+	if config.Probability <= 0 || config.Probability > 1 {
+		return nil, errors.New("mutation probability has to be between 0 and 1")
+	}
+	var decay DecaySchedule
+	if config.Decay != nil {
+		if config.Type != "uniform" && config.Type != "gaussian" {
+			return nil, errors.New("only uniform and gaussian mutations can decay")
+		}
+		if err := config.Decay.Validate(); err != nil {
+			return nil, err
+		}
+		decay = *config.Decay
+	}
+
+	switch config.Type {
+	case "uniform":
+		if config.Strength < 0 {
+			return nil, errors.New("uniform mutation's strength can't be negative")
+		}
+		return UniformMutation{Strength: config.Strength, Probability: config.Probability, Decay: decay}, nil
+	case "gaussian":
+		if config.Sigma < 0 {
+			return nil, errors.New("gaussian mutation's sigma can't be negative")
+		}
+		return GaussianMutation{Sigma: config.Sigma, Probability: config.Probability, Decay: decay}, nil
+	case "selfAdaptive":
+		if config.Sigma <= 0 {
+			return nil, errors.New("self-adaptive mutation's initial sigma has to be bigger than 0")
+		}
+		if config.LearningRate < 0 || config.MinSigma < 0 {
+			return nil, errors.New("self-adaptive mutation's learning rate and min sigma can't be negative")
+		}
+		return SelfAdaptiveMutation{
+			InitialSigma: config.Sigma,
+			LearningRate: config.LearningRate,
+			MinSigma:     config.MinSigma,
+			Probability:  config.Probability,
+		}, nil
+	case "reset":
+		if config.Range <= 0 {
+			return nil, errors.New("reset mutation's range has to be bigger than 0")
+		}
+		return ResetMutation{Range: config.Range, Probability: config.Probability}, nil
+	}
+	return nil, errors.New("unknown mutation: " + config.Type)
+}
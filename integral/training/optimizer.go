@@ -0,0 +1,135 @@
+package training
+
+import "math"
+
+// the learning rate of the default optimizer
+const defaultLearningRate = 0.5
+
+// prevents the optimizers from dividing by zero
+const optimizerEpsilon = 1e-8
+
+// Optimizer changes network's parameters using their gradients.
+// It can keep its own state for every parameter which is kept between the updates
+type Optimizer interface {
+	Update(parameters, gradients []float64)
+}
+
+// returns the state slice ready to be used for the given amount of parameters.
+// If the amount has changed the old state is dropped
+func prepareState(state []float64, numberOfParameters int) []float64 {
+	if len(state) != numberOfParameters {
+		return make([]float64, numberOfParameters)
+	}
+	return state
+}
+
+// The plain stochastic gradient descent
+type SGD struct {
+	LearningRate float64
+}
+
+func NewSGD(learningRate float64) *SGD {
+	return &SGD{LearningRate: learningRate}
+}
+
+func (optimizer *SGD) Update(parameters, gradients []float64) {
+	for i := range parameters {
+		parameters[i] -= optimizer.LearningRate * gradients[i]
+	}
+}
+
+// Gradient descent which keeps the velocity of every parameter.
+// With Nesterov set the gradient is taken as if the parameters were already moved by the velocity
+type Momentum struct {
+	LearningRate float64
+	Momentum     float64
+	Nesterov     bool
+	velocities   []float64
+}
+
+func NewMomentum(learningRate, momentum float64, nesterov bool) *Momentum {
+	return &Momentum{LearningRate: learningRate, Momentum: momentum, Nesterov: nesterov}
+}
+
+func (optimizer *Momentum) Update(parameters, gradients []float64) {
+	optimizer.velocities = prepareState(optimizer.velocities, len(parameters))
+	for i := range parameters {
+		optimizer.velocities[i] = optimizer.Momentum*optimizer.velocities[i] - optimizer.LearningRate*gradients[i]
+		if optimizer.Nesterov {
+			parameters[i] += optimizer.Momentum*optimizer.velocities[i] - optimizer.LearningRate*gradients[i]
+		} else {
+			parameters[i] += optimizer.velocities[i]
+		}
+	}
+}
+
+// Gradient descent which divides the learning rate by the decaying average of squared gradients
+type RMSProp struct {
+	LearningRate    float64
+	Decay           float64
+	squaredAverages []float64
+}
+
+func NewRMSProp(learningRate, decay float64) *RMSProp {
+	return &RMSProp{LearningRate: learningRate, Decay: decay}
+}
+
+func (optimizer *RMSProp) Update(parameters, gradients []float64) {
+	optimizer.squaredAverages = prepareState(optimizer.squaredAverages, len(parameters))
+	for i := range parameters {
+		optimizer.squaredAverages[i] = optimizer.Decay*optimizer.squaredAverages[i] + (1-optimizer.Decay)*gradients[i]*gradients[i]
+		parameters[i] -= optimizer.LearningRate * gradients[i] / (math.Sqrt(optimizer.squaredAverages[i]) + optimizerEpsilon)
+	}
+}
+
+// Gradient descent which divides the learning rate by the sum of all previous squared gradients
+type Adagrad struct {
+	LearningRate float64
+	squaredSums  []float64
+}
+
+func NewAdagrad(learningRate float64) *Adagrad {
+	return &Adagrad{LearningRate: learningRate}
+}
+
+func (optimizer *Adagrad) Update(parameters, gradients []float64) {
+	optimizer.squaredSums = prepareState(optimizer.squaredSums, len(parameters))
+	for i := range parameters {
+		optimizer.squaredSums[i] += gradients[i] * gradients[i]
+		parameters[i] -= optimizer.LearningRate * gradients[i] / (math.Sqrt(optimizer.squaredSums[i]) + optimizerEpsilon)
+	}
+}
+
+// Gradient descent which keeps decaying averages of gradients (first moments)
+// and of squared gradients (second moments) corrected for their initial bias towards zero
+type Adam struct {
+	LearningRate  float64
+	Beta1         float64
+	Beta2         float64
+	firstMoments  []float64
+	secondMoments []float64
+	step          int
+}
+
+func NewAdam(learningRate, beta1, beta2 float64) *Adam {
+	return &Adam{LearningRate: learningRate, Beta1: beta1, Beta2: beta2}
+}
+
+func (optimizer *Adam) Update(parameters, gradients []float64) {
+	if len(optimizer.firstMoments) != len(parameters) {
+		optimizer.step = 0
+	}
+	optimizer.firstMoments = prepareState(optimizer.firstMoments, len(parameters))
+	optimizer.secondMoments = prepareState(optimizer.secondMoments, len(parameters))
+	optimizer.step++
+
+	firstCorrection := 1 - math.Pow(optimizer.Beta1, float64(optimizer.step))
+	secondCorrection := 1 - math.Pow(optimizer.Beta2, float64(optimizer.step))
+	for i := range parameters {
+		optimizer.firstMoments[i] = optimizer.Beta1*optimizer.firstMoments[i] + (1-optimizer.Beta1)*gradients[i]
+		optimizer.secondMoments[i] = optimizer.Beta2*optimizer.secondMoments[i] + (1-optimizer.Beta2)*gradients[i]*gradients[i]
+		firstMoment := optimizer.firstMoments[i] / firstCorrection
+		secondMoment := optimizer.secondMoments[i] / secondCorrection
+		parameters[i] -= optimizer.LearningRate * firstMoment / (math.Sqrt(secondMoment) + optimizerEpsilon)
+	}
+}
@@ -0,0 +1,27 @@
+package training
+
+// A random numbers source (splitmix64) which whole state is a single number,
+// so it can be saved with the trainer and restored later
+type randomSource struct {
+	state uint64
+}
+
+func newRandomSource(seed int64) *randomSource {
+	return &randomSource{state: uint64(seed)}
+}
+
+func (source *randomSource) Seed(seed int64) {
+	source.state = uint64(seed)
+}
+
+func (source *randomSource) Uint64() uint64 {
+	source.state += 0x9e3779b97f4a7c15
+	z := source.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (source *randomSource) Int63() int64 {
+	return int64(source.Uint64() >> 1)
+}
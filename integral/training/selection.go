@@ -0,0 +1,244 @@
+package training
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Selector chooses networks which become parents of the next generation's children
+type Selector interface {
+	// Returns indexes of amount chosen networks, a network can be chosen many times.
+	// Costs are sorted from the lowest one, so the index 0 is the best network
+	Select(random *rand.Rand, costs []float64, amount int) []int
+}
+
+// Every network has the same chance
+type UniformSelection struct{}
+
+func (UniformSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	parents := make([]int, amount)
+	for i := range parents {
+		parents[i] = random.Intn(len(costs))
+	}
+	return parents
+}
+
+// The chance of the network is Ratio times smaller than the chance of the one better than it. Ratio is in (0, 1)
+type GeometricSelection struct {
+	Ratio float64
+}
+
+func (selection GeometricSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	weights := make([]float64, len(costs))
+	for i := range weights {
+		weights[i] = math.Pow(selection.Ratio, float64(i))
+	}
+	return selectWeighted(random, weights, amount)
+}
+
+// The best of Size networks drawn at random wins. Bigger tournaments give a bigger selection pressure
+type TournamentSelection struct {
+	Size int
+}
+
+func (selection TournamentSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	parents := make([]int, amount)
+	for i := range parents {
+		parents[i] = random.Intn(len(costs))
+		for j := 1; j < selection.Size; j++ {
+			// costs are sorted, so a lower index is a better network
+			if contender := random.Intn(len(costs)); contender < parents[i] {
+				parents[i] = contender
+			}
+		}
+	}
+	return parents
+}
+
+// The chance of the network is proportional to its fitness. The fitness is how much lower its cost is
+// than the worst network's cost, so the worst network is never chosen unless all costs are the same
+type RouletteWheelSelection struct{}
+
+func (RouletteWheelSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	return selectWeighted(random, getFitness(costs), amount)
+}
+
+// The chance of the network decreases linearly with its rank. Pressure in [1, 2] is how many times
+// the best network's chance is bigger than the average one, the worst network's chance is 2 - Pressure times the average
+type RankSelection struct {
+	Pressure float64
+}
+
+func (selection RankSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	weights := make([]float64, len(costs))
+	for i := range weights {
+		weights[i] = selection.Pressure
+		if len(costs) > 1 {
+			weights[i] -= 2 * (selection.Pressure - 1) * float64(i) / float64(len(costs)-1)
+		}
+	}
+	return selectWeighted(random, weights, amount)
+}
+
+// Chooses networks proportionally to their fitness, like the roulette wheel selection, but with evenly spaced
+// pointers on a single spin. The number of times a network is chosen is as close to its expected one as possible
+type StochasticUniversalSampling struct{}
+
+func (StochasticUniversalSampling) Select(random *rand.Rand, costs []float64, amount int) []int {
+	weights := getFitness(costs)
+	sum := 0.0
+	for _, weight := range weights {
+		sum += weight
+	}
+
+	parents := make([]int, 0, amount)
+	distance := sum / float64(amount)
+	pointer := random.Float64() * distance
+	currentWeight := weights[0]
+	for index := 0; len(parents) < amount; pointer += distance {
+		for pointer > currentWeight && index < len(weights)-1 {
+			index++
+			currentWeight += weights[index]
+		}
+		parents = append(parents, index)
+	}
+	// the pointers choose networks in order, so they are shuffled not to pair the same ones
+	random.Shuffle(len(parents), func(i, j int) {
+		parents[i], parents[j] = parents[j], parents[i]
+	})
+	return parents
+}
+
+// Only the Fraction of the best networks can be chosen, all of them with the same chance. Fraction is in (0, 1]
+type TruncationSelection struct {
+	Fraction float64
+}
+
+func (selection TruncationSelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	numberOfBest := int(math.Ceil(float64(len(costs)) * selection.Fraction))
+	if numberOfBest > len(costs) {
+		numberOfBest = len(costs)
+	}
+	return UniformSelection{}.Select(random, costs[:numberOfBest], amount)
+}
+
+// returns how much lower every cost is than the worst one. If all costs are the same every fitness is 1
+func getFitness(costs []float64) []float64 {
+	worst := costs[0]
+	for _, cost := range costs {
+		worst = math.Max(worst, cost)
+	}
+	fitness := make([]float64, len(costs))
+	sum := 0.0
+	for i, cost := range costs {
+		fitness[i] = worst - cost
+		sum += fitness[i]
+	}
+	if sum == 0 || math.IsNaN(sum) || math.IsInf(sum, 0) {
+		for i := range fitness {
+			fitness[i] = 1
+		}
+	}
+	return fitness
+}
+
+// checks if hyperparameters of the selectors defined by this module are in their ranges
+func ValidateSelector(selector Selector) error {
+	config, err := NewSelectionConfig(selector)
+	if err != nil {
+		// other selectors can't be checked
+		return nil
+	}
+	_, err = config.NewSelector()
+	return err
+}
+
+// returns amount indexes drawn with chances proportional to the weights
+func selectWeighted(random *rand.Rand, weights []float64, amount int) []int {
+	cumulative := make([]float64, len(weights))
+	sum := 0.0
+	for i, weight := range weights {
+		sum += weight
+		cumulative[i] = sum
+	}
+
+	parents := make([]int, amount)
+	for i := range parents {
+		parents[i] = sort.SearchFloat64s(cumulative, random.Float64()*sum)
+		if parents[i] == len(weights) {
+			parents[i] = len(weights) - 1
+		}
+	}
+	return parents
+}
+
+// Describes a selector so it can be read from or written to a configuration file
+type SelectionConfig struct {
+	// uniform, geometric, tournament, rouletteWheel, rank, stochasticUniversalSampling or truncation
+	Type string `json:"type"`
+	// used only by geometric
+	Ratio float64 `json:"ratio,omitempty"`
+	// used only by tournament
+	TournamentSize int `json:"tournamentSize,omitempty"`
+	// used only by rank
+	Pressure float64 `json:"pressure,omitempty"`
+	// used only by truncation
+	Fraction float64 `json:"fraction,omitempty"`
+}
+
+// returns the description of the given selector. Only the selectors defined by this module can be described
+func NewSelectionConfig(selector Selector) (SelectionConfig, error) {
+	switch mySelector := selector.(type) {
+	case UniformSelection:
+		return SelectionConfig{Type: "uniform"}, nil
+	case GeometricSelection:
+		return SelectionConfig{Type: "geometric", Ratio: mySelector.Ratio}, nil
+	case TournamentSelection:
+		return SelectionConfig{Type: "tournament", TournamentSize: mySelector.Size}, nil
+	case RouletteWheelSelection:
+		return SelectionConfig{Type: "rouletteWheel"}, nil
+	case RankSelection:
+		return SelectionConfig{Type: "rank", Pressure: mySelector.Pressure}, nil
+	case StochasticUniversalSampling:
+		return SelectionConfig{Type: "stochasticUniversalSampling"}, nil
+	case TruncationSelection:
+		return SelectionConfig{Type: "truncation", Fraction: mySelector.Fraction}, nil
+	}
+	return SelectionConfig{}, fmt.Errorf("selector %T can't be described", selector)
+}
+
+// returns the described selector
+func (config SelectionConfig) NewSelector() (Selector, error) {
+	switch config.Type {
+	case "uniform":
+		return UniformSelection{}, nil
+	case "geometric":
+		if config.Ratio <= 0 || config.Ratio >= 1 {
+			return nil, errors.New("geometric selection's ratio has to be between 0 and 1")
+		}
+		return GeometricSelection{Ratio: config.Ratio}, nil
+	case "tournament":
+		if config.TournamentSize <= 0 {
+			return nil, errors.New("tournament size has to be bigger than 0")
+		}
+		return TournamentSelection{Size: config.TournamentSize}, nil
+	case "rouletteWheel":
+		return RouletteWheelSelection{}, nil
+	case "rank":
+		if config.Pressure < 1 || config.Pressure > 2 {
+			return nil, errors.New("rank selection's pressure has to be between 1 and 2")
+		}
+		return RankSelection{Pressure: config.Pressure}, nil
+	case "stochasticUniversalSampling":
+		return StochasticUniversalSampling{}, nil
+	case "truncation":
+		if config.Fraction <= 0 || config.Fraction > 1 {
+			return nil, errors.New("truncation selection's fraction has to be between 0 and 1")
+		}
+		return TruncationSelection{Fraction: config.Fraction}, nil
+	}
+	return nil, errors.New("unknown selection: " + config.Type)
+}
@@ -1,35 +1,74 @@
 package training
 
 import (
+	"context"
+	"errors"
+	"math/rand"
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
 )
 
-// TODO:
-// Configuration (In the future from the json file)
-const evolutionTraining = true
+// Algorithm determines how the trainer changes the networks' weights and biases
+type Algorithm int
 
-// const backPropagationTraining = false
+const (
+	// networks are mated and mutated and only the best of them survive
+	Evolution Algorithm = iota
+	// the first network is trained with the gradient descent
+	BackPropagation
+)
 
 type Trainer struct {
 	networks         []network.Network
 	numberOfNetworks int
 	trainDataSets    network.DataSets
-	Initialized      bool
+	optimizer        Optimizer
+	loss             network.Loss
+	evolution        EvolutionConfig
+	selector         Selector      // if nil parents are chosen as the evolution config says
+	crossover        Crossover     // if nil genes are taken from both parents with the same chance
+	genomeLayout     *GenomeLayout // built at the first use as the networks' structure doesn't change
+	mutator          Mutator       // if nil genes are mutated as the evolution config says
+	mutationHistory  []MutationStatistics
+	batch            BatchConfig
+	epochOrder       []int // the order of training data sets in the current epoch
+	epochPosition    int   // the number of data sets already used in the current epoch
+	// if it is set the best network is measured on it after every generation and the networks are ranked by it
+	validationDataSets network.DataSets
+	validationHistory  []ValidationResult
+	heldOut            []bool               // which of the owner's data sets are held out for the validation, only saved in checkpoints
+	earlyStopping      *EarlyStoppingConfig // if nil the training isn't stopped early
+	stoppedEarly       bool
+	callbacks          []Callback
+	events             chan<- TrainingEvent
+	epoch              int // number of all epochs ended by the trainer
+	// the last training was cancelled, so the costs are out of date and the first network is the best one
+	interrupted  bool
+	generation   int // number of all training iterations done by the trainer
+	randomSource *randomSource
+	random       *rand.Rand
+	Initialized  bool
 }
 
-// Initializes the trainer and creates training networks
-func NewTrainer(originalNet network.Network, numberOfNet int) Trainer {
+// Initializes the trainer and creates training networks.
+// A copy of the original network becomes the first training network.
+// All random numbers used by the trainer come from the seed, so the same seed gives the same training
+func NewTrainer(originalNet network.Network, numberOfNet int, seed int64) Trainer {
 	var trainer Trainer
 	trainer.numberOfNetworks = numberOfNet
-	trainer.networks = append(trainer.networks, originalNet)
+	trainer.optimizer = NewSGD(defaultLearningRate)
+	trainer.loss = network.MeanSquaredError{}
+	trainer.evolution = DefaultEvolutionConfig()
+	trainer.setRandomSource(newRandomSource(seed))
+	trainer.networks = append(trainer.networks, originalNet.CopyNetwork())
 	// creates new training networks and initializes them
 	for len(trainer.networks) < trainer.numberOfNetworks {
 		var newNet network.Network
-		newNet.InitializeNetwork(originalNet.GetNetworkStructure(), originalNet.GetOutputLabels())
+		newNet.InitializeNetwork(trainer.random, originalNet.GetNetworkStructure(), originalNet.GetOutputLabels(), originalNet.GetActivations(), originalNet.GetInitializers())
 		trainer.networks = append(trainer.networks, newNet)
 	}
 
@@ -37,63 +76,320 @@ func NewTrainer(originalNet network.Network, numberOfNet int) Trainer {
 	return trainer
 }
 
-// trains the network iterations times with training dataset
-func (trainer *Trainer) Train(dataSets network.DataSets, iterations int) error {
-	trainer.trainDataSets = dataSets
+// sets the source of random numbers used for mating and mutating networks
+func (trainer *Trainer) setRandomSource(source *randomSource) {
+	trainer.randomSource = source
+	trainer.random = rand.New(source)
+}
+
+// Sets the optimizer used by the back propagation.
+// Its state is kept between the trainings until it is replaced
+func (trainer *Trainer) SetOptimizer(optimizer Optimizer) {
+	trainer.optimizer = optimizer
+}
+
+// Sets the hyperparameters of the evolution algorithm
+func (trainer *Trainer) SetEvolutionConfig(config EvolutionConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	trainer.evolution = config
+	return nil
+}
+
+// Sets how the evolution chooses parents. Nil brings back the choice described by the evolution config
+func (trainer *Trainer) SetSelector(selector Selector) error {
+	if err := ValidateSelector(selector); err != nil {
+		return err
+	}
+	trainer.selector = selector
+	return nil
+}
+
+// returns the selector which chooses parents of the evolution's children
+func (trainer *Trainer) GetSelector() Selector {
+	return trainer.getSelector()
+}
+
+// returns the set selector or the one described by the evolution config
+func (trainer *Trainer) getSelector() Selector {
+	if trainer.selector != nil {
+		return trainer.selector
+	}
+	if trainer.evolution.FavourBestNetworksWhileMating {
+		return GeometricSelection{Ratio: trainer.evolution.MaxNetworksSurvivorsWeight}
+	}
+	return UniformSelection{}
+}
+
+// Sets how the evolution mixes parents' genes. Nil brings back the uniform crossover with the bias of 0.5
+func (trainer *Trainer) SetCrossover(crossover Crossover) error {
+	if err := ValidateCrossover(crossover); err != nil {
+		return err
+	}
+	trainer.crossover = crossover
+	return nil
+}
+
+// returns the crossover which mixes parents' genes
+func (trainer *Trainer) GetCrossover() Crossover {
+	if trainer.crossover != nil {
+		return trainer.crossover
+	}
+	return UniformCrossover{Bias: 0.5}
+}
+
+// Sets how the evolution mutates children. Nil brings back the uniform mutation of every gene
+// within ±StrengthOfEvolution of the evolution config
+func (trainer *Trainer) SetMutator(mutator Mutator) error {
+	if err := ValidateMutator(mutator); err != nil {
+		return err
+	}
+	trainer.mutator = mutator
+	return nil
+}
+
+// returns the mutator which changes the evolution's children
+func (trainer *Trainer) GetMutator() Mutator {
+	if trainer.mutator != nil {
+		return trainer.mutator
+	}
+	return UniformMutation{Strength: trainer.evolution.StrengthOfEvolution, Probability: 1}
+}
+
+// returns how much the children of every generation trained with the evolution were mutated
+func (trainer *Trainer) GetMutationHistory() []MutationStatistics {
+	return append([]MutationStatistics(nil), trainer.mutationHistory...)
+}
+
+// Sets how the training data is split into batches
+func (trainer *Trainer) SetBatchConfig(config BatchConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	trainer.batch = config
+	return nil
+}
+
+// returns how the training data is split into batches
+func (trainer *Trainer) GetBatchConfig() BatchConfig {
+	return trainer.batch
+}
+
+// returns the hyperparameters of the evolution algorithm
+func (trainer *Trainer) GetEvolutionConfig() EvolutionConfig {
+	return trainer.evolution
+}
+
+// returns the optimizer used by the back propagation
+func (trainer *Trainer) GetOptimizer() Optimizer {
+	return trainer.optimizer
+}
+
+// returns the loss which measures the cost of the networks
+func (trainer *Trainer) GetLoss() network.Loss {
+	return trainer.loss
+}
+
+// returns the structure of the trained networks - number of layers and nodes per layer
+func (trainer *Trainer) GetNetworkStructure() []int {
+	return trainer.networks[0].GetNetworkStructure()
+}
 
-	for i := 0; i < iterations; i++ {
-		if evolutionTraining {
-			err := trainer.evolutionTraining()
-			if err != nil {
+// returns whether the trained networks have the same structure, output labels and activations as the given one
+func (trainer *Trainer) HasSameArchitecture(net *network.Network) bool {
+	return trainer.networks[0].HasSameArchitecture(net)
+}
+
+// returns the number of networks which survive every generation
+func (trainer *Trainer) GetNumberOfNetworks() int {
+	return trainer.numberOfNetworks
+}
+
+// returns the number of all training iterations done by the trainer
+func (trainer *Trainer) GetGeneration() int {
+	return trainer.generation
+}
+
+// Sets the loss which measures the cost of the networks for both evolution and back propagation
+func (trainer *Trainer) SetLoss(loss network.Loss) {
+	trainer.loss = loss
+}
+
+// Trains the network iterations times with training dataset using the given algorithm.
+// If the validation data is set, the best network is measured on it after every iteration,
+// the training can be stopped early and at the end the networks are ranked by their validation costs.
+// Callbacks and the event channel are notified about every generation, epoch and the end of the training
+func (trainer *Trainer) Train(dataSets network.DataSets, iterations int, algorithm Algorithm) error {
+	return trainer.TrainContext(context.Background(), dataSets, iterations, algorithm)
+}
+
+// Trains like Train, but stops when the context is done. The unfinished generation is dropped,
+// except for the batches already used by the back propagation, and the context's error is returned.
+// The best networks are then the ones found so far, but they aren't measured again
+func (trainer *Trainer) TrainContext(ctx context.Context, dataSets network.DataSets, iterations int, algorithm Algorithm) error {
+	if algorithm != Evolution && algorithm != BackPropagation {
+		return errors.New("unknown training algorithm")
+	}
+	if len(dataSets) == 0 {
+		return errors.New("there is no training data")
+	}
+	batchSize := trainer.batch.getBatchSize(algorithm, len(dataSets))
+	if trainer.batch.DropLast && batchSize > len(dataSets) {
+		return errors.New("batch size can't be bigger than the number of training data sets if the last batch is dropped")
+	}
+	if trainer.earlyStopping != nil && len(trainer.validationDataSets) == 0 {
+		return errors.New("early stopping requires the validation data")
+	}
+	trainer.setTrainDataSets(dataSets)
+	trainer.stoppedEarly = false
+	trainer.interrupted = false
+	state := newEarlyStopping()
+
+	trainingStart := time.Now()
+	for i := 0; i < iterations && !trainer.stoppedEarly; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		generationStart := time.Now()
+		if err := trainer.notify(ctx, trainer.newEvent(GenerationStart, algorithm, trainingStart)); err != nil {
+			if !errors.Is(err, ErrStopTraining) {
+				return err
+			}
+			trainer.stoppedEarly = true
+			break
+		}
+
+		var err error
+		switch algorithm {
+		case Evolution:
+			err = trainer.evolutionTraining(ctx, trainer.nextEvolutionBatch(batchSize))
+		case BackPropagation:
+			err = trainer.backPropagationTraining(ctx, batchSize)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
 				return err
 			}
+			break
+		}
+		trainer.generation++
+		if len(trainer.validationDataSets) != 0 && trainer.validate(&state) {
+			trainer.stoppedEarly = true
+		}
+
+		epochEnded := trainer.epochEnded(batchSize)
+		if epochEnded {
+			trainer.epoch++
+		}
+		err = trainer.notify(ctx, trainer.newGenerationEndEvent(algorithm, trainingStart, generationStart))
+		if err == nil && epochEnded {
+			err = trainer.notify(ctx, trainer.newEvent(EpochEnd, algorithm, trainingStart))
+		}
+		if err != nil {
+			if !errors.Is(err, ErrStopTraining) {
+				return err
+			}
+			trainer.stoppedEarly = true
 		}
-		// TODO: add back propagation support
-		// if backPropagationTraining {
-		// 	//w
-		// }
 	}
-	return nil
+	trainer.restoreBest(&state)
+	// keeps the costs up to date so the best networks can be found
+	trainer.interrupted = ctx.Err() != nil
+	if !trainer.interrupted {
+		if len(trainer.validationDataSets) != 0 {
+			calculateAverageCosts(&trainer.networks, trainer.validationDataSets, trainer.loss)
+		} else {
+			calculateAverageCosts(&trainer.networks, trainer.trainDataSets, trainer.loss)
+		}
+	}
+
+	event := trainer.newEvent(TrainEnd, algorithm, trainingStart)
+	event.Duration = event.Elapsed
+	event.BestCost, event.AverageCost, event.WorstCost = trainer.getCostsSummary()
+	event.StoppedEarly = trainer.stoppedEarly
+	if err := trainer.notify(ctx, event); err != nil && !errors.Is(err, ErrStopTraining) {
+		return err
+	}
+	return ctx.Err()
 }
 
-// calculate concurrently an average cost for every network for all training datasets
-// and add them to trainer's costs map
-func calculateAverageCosts(networks *[]network.Network, dataSets network.DataSets) {
+// Returns a copy of the network with the lowest cost measured at the end of the last training.
+// The cost is measured on the validation data if it is set, otherwise on the training data
+func (trainer *Trainer) Best() network.Network {
+	return trainer.BestNetworks(1)[0]
+}
+
+// Returns copies of the given amount of networks with the lowest costs measured at the end of the last training.
+// The best network is the first one
+func (trainer *Trainer) BestNetworks(amount int) []network.Network {
+	sortedNetworks := getSortedNetworks(&trainer.networks)
+	if trainer.interrupted {
+		// after every finished generation the first network is the best one
+		for i := range sortedNetworks {
+			if sortedNetworks[i] == &trainer.networks[0] {
+				copy(sortedNetworks[1:i+1], sortedNetworks[:i])
+				sortedNetworks[0] = &trainer.networks[0]
+				break
+			}
+		}
+	}
+	if amount > len(sortedNetworks) {
+		amount = len(sortedNetworks)
+	}
+
+	bestNetworks := make([]network.Network, amount)
+	for i := range bestNetworks {
+		bestNetworks[i] = sortedNetworks[i].CopyNetwork()
+	}
+	return bestNetworks
+}
+
+// calculate concurrently an average cost measured by the loss for every network for all training datasets
+func calculateAverageCosts(networks *[]network.Network, dataSets network.DataSets, loss network.Loss) {
+	calculateAverageCostsContext(context.Background(), networks, dataSets, loss)
+}
+
+// calculates costs like calculateAverageCosts, but stops when the context is done and returns its error.
+// Then some networks can have their old costs
+func calculateAverageCostsContext(ctx context.Context, networks *[]network.Network, dataSets network.DataSets, loss network.Loss) error {
 	numberOfWorkers := runtime.NumCPU()
 	netChan := make(chan *network.Network)
 	var wg sync.WaitGroup
-	wg.Add(len(*networks))
+	wg.Add(numberOfWorkers)
 	lock := sync.Mutex{}
 
 	for i := 0; i < numberOfWorkers; i++ {
 		go func(wg *sync.WaitGroup, netChan chan *network.Network) {
+			defer wg.Done()
 			for net := range netChan {
-				net.CalculateCost(&lock, dataSets)
-				wg.Done()
+				net.CalculateCostContext(ctx, &lock, dataSets, loss)
 			}
 		}(&wg, netChan)
 	}
 
+sending:
 	for i := range *networks {
-		netChan <- &(*networks)[i]
+		select {
+		case netChan <- &(*networks)[i]:
+		case <-ctx.Done():
+			break sending
+		}
 	}
 	close(netChan)
 	wg.Wait()
+	return ctx.Err()
 }
 
 // returns networks [0] <-- the best [n] <-- worse
 func getSortedNetworks(networks *[]network.Network) []*network.Network {
-	m := make(map[float64]*network.Network)
-	sortValues := make([]float64, len(*networks))
 	sortedNetworks := make([]*network.Network, len(*networks))
 	for i := range *networks {
-		m[(*networks)[i].GetCost()] = &(*networks)[i]
-		sortValues[i] = (*networks)[i].GetCost()
+		sortedNetworks[i] = &(*networks)[i]
 	}
 
-	sort.Float64s(sortValues)
-	for i := range sortedNetworks {
-		sortedNetworks[i] = m[sortValues[i]]
-	}
+	sort.SliceStable(sortedNetworks, func(i, j int) bool {
+		return sortedNetworks[i].GetCost() < sortedNetworks[j].GetCost()
+	})
 	return sortedNetworks
 }
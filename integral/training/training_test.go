@@ -1,20 +1,30 @@
 package training
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"strings"
+	"sync"
 	"testing"
-	"time"
 
 	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
 )
 
-func createDummyNetworkTrainer() *Trainer {
-	// for testing purposes
-	rand.Seed(time.Now().UnixNano())
+// the seed of all random numbers used by the tests, so their results can be reproduced
+const testSeed = 1
+
+func newTestRandom() *rand.Rand {
+	return rand.New(rand.NewSource(testSeed))
+}
 
+func createDummyNetworkTrainer() *Trainer {
 	var net network.Network
-	net.InitializeNetwork([]int{3, 6, 3}, []string{"1", "2", "3"})
-	trainer := NewTrainer(net, 20)
+	net.InitializeNetwork(newTestRandom(), []int{3, 6, 3}, []string{"1", "2", "3"}, nil, nil)
+	trainer := NewTrainer(net, 20, testSeed)
 	return &trainer
 }
 
@@ -42,12 +52,12 @@ func TestCreatingNewGeneration(t *testing.T) {
 		trainer := createDummyNetworkTrainer()
 		trainer.trainDataSets = goodData[0]
 
-		calculateAverageCosts(&trainer.networks, trainer.trainDataSets)
-		err := trainer.createNewFavouredGeneration(getSortedNetworks(&trainer.networks))
+		calculateAverageCosts(&trainer.networks, trainer.trainDataSets, trainer.loss)
+		err := trainer.createNewGeneration(context.Background(), trainer.trainDataSets)
 		if err != nil {
 			t.Fatal(err, myData)
 		}
-		if len(trainer.networks) != trainer.numberOfNetworks+int(float64(trainer.numberOfNetworks)*percentageOfChildrenToParents) {
+		if len(trainer.networks) != trainer.numberOfNetworks+int(float64(trainer.numberOfNetworks)*trainer.evolution.PercentageOfChildrenToParents) {
 			t.Fatal("number of new generation networks is incorrect: ", len(trainer.networks)-trainer.numberOfNetworks)
 		}
 	}
@@ -56,11 +66,12 @@ func TestCreatingNewGeneration(t *testing.T) {
 // generates random colors and returns the color and wheter it is red or not
 func createTrainingData(numberOfData int) network.DataSets {
 	var dataSets network.DataSets
+	random := newTestRandom()
 	for i := 0; i < numberOfData; i++ {
 		input := make([]float64, 3)
-		red := rand.Intn(256)
-		green := rand.Intn(256)
-		blue := rand.Intn(256)
+		red := random.Intn(256)
+		green := random.Intn(256)
+		blue := random.Intn(256)
 		input[0] = float64(red) / 255
 		input[1] = float64(green) / 255
 		input[2] = float64(blue) / 255
@@ -91,21 +102,18 @@ func getNetworkAccuracy(trainer *Trainer) float64 {
 }
 
 func TestEvolution(t *testing.T) {
-	// for testing purposes
-	rand.Seed(time.Now().UnixNano())
-
 	var net network.Network
-	net.InitializeNetwork([]int{3, 3, 2}, []string{"red", "notRed"})
-	trainer := NewTrainer(net, 10)
+	net.InitializeNetwork(newTestRandom(), []int{3, 3, 2}, []string{"red", "notRed"}, nil, nil)
+	trainer := NewTrainer(net, 10, testSeed)
 
 	trainer.trainDataSets = createTrainingData(100)
 
-	calculateAverageCosts(&trainer.networks, trainer.trainDataSets)
+	calculateAverageCosts(&trainer.networks, trainer.trainDataSets, trainer.loss)
 	beforeAccuracy := getNetworkAccuracy(&trainer)
 	for i := 0; i < 100; i++ {
-		trainer.evolutionTraining()
+		trainer.evolutionTraining(context.Background(), trainer.trainDataSets)
 	}
-	calculateAverageCosts(&trainer.networks, trainer.trainDataSets)
+	calculateAverageCosts(&trainer.networks, trainer.trainDataSets, trainer.loss)
 	afterAccuracy := getNetworkAccuracy(&trainer)
 
 	if afterAccuracy < beforeAccuracy {
@@ -113,3 +121,1174 @@ func TestEvolution(t *testing.T) {
 	}
 
 }
+
+/////////////////////////////////////////////////////////////
+////			    Back Propagation Tests			     ////
+/////////////////////////////////////////////////////////////
+
+// compares calculated gradients with the ones approximated from the change of cost
+func checkGradients(t *testing.T, net network.Network, loss network.Loss) {
+	dataSets := createTrainingData(1)
+
+	const epsilon = 1e-6
+	gradients := net.CalculateGradients(dataSets[0], loss)
+	parameters := net.GetRawParameters()
+	for i := range parameters {
+		original := parameters[i]
+		parameters[i] = original + epsilon
+		net.CalculateCost(&sync.Mutex{}, dataSets, loss)
+		higherCost := net.GetCost()
+
+		parameters[i] = original - epsilon
+		net.CalculateCost(&sync.Mutex{}, dataSets, loss)
+		lowerCost := net.GetCost()
+
+		parameters[i] = original
+
+		approximated := (higherCost - lowerCost) / (2 * epsilon)
+		if math.Abs(approximated-gradients[i]) > 1e-5 {
+			t.Fatal(net.GetActivations(), loss, " - gradient of parameter ", i, " is incorrect: ", gradients[i], " expected: ", approximated)
+		}
+	}
+}
+
+func TestGradients(t *testing.T) {
+	activations := []network.Activation{
+		network.Sigmoid, network.Tanh, network.ReLU, network.LeakyReLU, network.ELU,
+		network.GELU, network.Softplus, network.Linear, network.Softmax,
+	}
+	for _, activation := range activations {
+		var net network.Network
+		net.InitializeNetwork(newTestRandom(), []int{3, 4, 3, 2}, []string{"red", "notRed"}, []network.Activation{activation, network.Tanh, activation}, nil)
+		checkGradients(t, net, network.MeanSquaredError{})
+	}
+
+	type lossData struct {
+		loss             network.Loss
+		outputActivation network.Activation
+	}
+	losses := []lossData{
+		{network.MeanSquaredError{}, network.Sigmoid},
+		{network.BinaryCrossEntropy{}, network.Sigmoid},
+		{network.CategoricalCrossEntropy{}, network.Softmax},
+		{network.Hinge{}, network.Tanh},
+		{network.Huber{Delta: 0.5}, network.Linear},
+	}
+	for _, data := range losses {
+		var net network.Network
+		net.InitializeNetwork(newTestRandom(), []int{3, 4, 2}, []string{"red", "notRed"}, []network.Activation{network.Tanh, data.outputActivation}, nil)
+		checkGradients(t, net, data.loss)
+	}
+}
+
+func TestBackPropagation(t *testing.T) {
+	var net network.Network
+	net.InitializeNetwork(newTestRandom(), []int{3, 3, 2}, []string{"red", "notRed"}, nil, nil)
+	trainer := NewTrainer(net, 1, testSeed)
+	dataSets := createTrainingData(100)
+
+	calculateAverageCosts(&trainer.networks, dataSets, trainer.loss)
+	beforeCost := trainer.networks[0].GetCost()
+	if err := trainer.Train(dataSets, 50, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	calculateAverageCosts(&trainer.networks, dataSets, trainer.loss)
+	afterCost := trainer.networks[0].GetCost()
+
+	if afterCost >= beforeCost {
+		t.Fatal("Back propagation - the cost hasn't decreased: ", beforeCost, afterCost)
+	}
+	if err := trainer.Train(dataSets, 1, Algorithm(-1)); err == nil {
+		t.Fatal("unknown training algorithm got through")
+	}
+}
+
+func TestOptimizers(t *testing.T) {
+	optimizers := []Optimizer{
+		NewSGD(0.1),
+		NewMomentum(0.1, 0.9, false),
+		NewMomentum(0.1, 0.9, true),
+		NewRMSProp(0.01, 0.9),
+		NewAdagrad(0.5),
+		NewAdam(0.05, 0.9, 0.999),
+	}
+	for _, optimizer := range optimizers {
+		// minimizes (x-3)^2 + (y+1)^2
+		parameters := []float64{0, 0}
+		for i := 0; i < 500; i++ {
+			gradients := []float64{2 * (parameters[0] - 3), 2 * (parameters[1] + 1)}
+			optimizer.Update(parameters, gradients)
+		}
+		if math.Abs(parameters[0]-3) > 0.05 || math.Abs(parameters[1]+1) > 0.05 {
+			t.Fatalf("%T hasn't found the minimum: %v", optimizer, parameters)
+		}
+	}
+}
+
+func TestOptimizerStatePersistence(t *testing.T) {
+	var net network.Network
+	net.InitializeNetwork(newTestRandom(), []int{3, 3, 2}, []string{"red", "notRed"}, nil, nil)
+	trainer := NewTrainer(net, 1, testSeed)
+	adam := NewAdam(0.01, 0.9, 0.999)
+	trainer.SetOptimizer(adam)
+	dataSets := createTrainingData(10)
+
+	for i := 1; i <= 3; i++ {
+		if err := trainer.Train(dataSets, 1, BackPropagation); err != nil {
+			t.Fatal(err)
+		}
+		if adam.step != i*len(dataSets) {
+			t.Fatal("optimizer's state hasn't been kept between trainings: ", adam.step)
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////
+////			    	Checkpoint Tests				 ////
+/////////////////////////////////////////////////////////////
+
+// checks if both trainers have networks which give exactly the same outputs
+func compareTrainers(t *testing.T, first, second *Trainer, dataSets network.DataSets) {
+	if len(first.networks) != len(second.networks) || first.generation != second.generation {
+		t.Fatal("trainers have different number of networks or generations")
+	}
+	for i := range first.networks {
+		for _, data := range dataSets {
+			firstOutputs := first.networks[i].GetOutputs(data.GetInputs())
+			secondOutputs := second.networks[i].GetOutputs(data.GetInputs())
+			for j := range firstOutputs {
+				if firstOutputs[j] != secondOutputs[j] {
+					t.Fatal("network ", i, " gives different outputs: ", firstOutputs, secondOutputs)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckpointAndResume(t *testing.T) {
+	dataSets := createTrainingData(30)
+	for i, data := range dataSets {
+		dataSets[i].SetData(data.GetInputs(), []string{"1", "2", "3"}[i%3])
+	}
+	optimizers := []Optimizer{
+		NewSGD(0.1),
+		NewMomentum(0.1, 0.9, true),
+		NewRMSProp(0.01, 0.9),
+		NewAdagrad(0.1),
+		NewAdam(0.01, 0.9, 0.999),
+	}
+	for _, optimizer := range optimizers {
+		trainer := createDummyNetworkTrainer()
+		trainer.SetOptimizer(optimizer)
+		trainer.SetLoss(network.Huber{Delta: 0.5})
+		if err := trainer.Train(dataSets, 3, Evolution); err != nil {
+			t.Fatal(err)
+		}
+		if err := trainer.Train(dataSets, 2, BackPropagation); err != nil {
+			t.Fatal(err)
+		}
+
+		var buffer bytes.Buffer
+		if err := trainer.Checkpoint(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		resumedTrainer, err := ResumeTrainer(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resumedTrainer.GetGeneration() != 5 {
+			t.Fatal("generation counter hasn't been restored: ", resumedTrainer.GetGeneration())
+		}
+		compareTrainers(t, trainer, &resumedTrainer, dataSets)
+
+		for _, algorithm := range []Algorithm{Evolution, BackPropagation} {
+			if err := trainer.Train(dataSets, 2, algorithm); err != nil {
+				t.Fatal(err)
+			}
+			if err := resumedTrainer.Train(dataSets, 2, algorithm); err != nil {
+				t.Fatal(err)
+			}
+			compareTrainers(t, trainer, &resumedTrainer, dataSets)
+		}
+	}
+}
+
+type customOptimizer struct{}
+
+func (customOptimizer) Update(parameters, gradients []float64) {}
+
+func TestBadCheckpoints(t *testing.T) {
+	var buffer bytes.Buffer
+	trainer := createDummyNetworkTrainer()
+	trainer.SetOptimizer(customOptimizer{})
+	if err := trainer.Checkpoint(&buffer); err == nil {
+		t.Fatal("custom optimizer has been saved")
+	}
+	if err := (&Trainer{}).Checkpoint(&buffer); err == nil {
+		t.Fatal("not initialized trainer has been saved")
+	}
+
+	badCheckpoints := []string{
+		``,
+		`{"version":2,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"magic"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"magic"},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]},{"layers":[{"nodes":2}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"earlyStopping":{"patience":0},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"epochOrder":[0,0],"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"epochOrder":[1,0],"epochPosition":3,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":3,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}]},{"layers":[{"nodes":1}]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[{"layers":[{"nodes":1}],"outputLabels":["a"]},{"layers":[{"nodes":1}],"outputLabels":["b"]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[` +
+			`{"layers":[{"nodes":2}],"outputLabels":["a b","c"]},{"layers":[{"nodes":2}],"outputLabels":["a","b c"]}]}`,
+		`{"version":1,"numberOfNetworks":1,"loss":{"type":"hinge"},"optimizer":{"type":"sgd","learningRate":0.1},"networks":[` +
+			`{"layers":[{"nodes":1},{"nodes":1,"activation":"relu","biases":[0],"weights":[[1]]}]},` +
+			`{"layers":[{"nodes":1},{"nodes":1,"activation":"sigmoid","biases":[0],"weights":[[1]]}]}]}`,
+	}
+	for _, myCheckpoint := range badCheckpoints {
+		if _, err := ResumeTrainer(strings.NewReader(myCheckpoint)); err == nil {
+			t.Fatal("bad checkpoint got through: ", myCheckpoint)
+		}
+	}
+}
+
+func TestBestNetworks(t *testing.T) {
+	trainer := createDummyNetworkTrainer()
+	dataSets := createTrainingData(20)
+	for i, data := range dataSets {
+		dataSets[i].SetData(data.GetInputs(), []string{"1", "2", "3"}[i%3])
+	}
+	if err := trainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+
+	bestNetworks := trainer.BestNetworks(len(trainer.networks) + 5)
+	if len(bestNetworks) != len(trainer.networks) {
+		t.Fatal("wrong number of the best networks: ", len(bestNetworks))
+	}
+	for i := 1; i < len(bestNetworks); i++ {
+		if bestNetworks[i].GetCost() < bestNetworks[i-1].GetCost() {
+			t.Fatal("the best networks aren't sorted")
+		}
+	}
+	best := trainer.Best()
+	if best.GetCost() != bestNetworks[0].GetCost() {
+		t.Fatal("Best hasn't returned the network with the lowest cost")
+	}
+
+	// the returned network can't share memory with the trainer's one
+	parameters := best.GetRawParameters()
+	for i := range parameters {
+		parameters[i] = 0
+	}
+	newBest := trainer.Best()
+	if newBest.GetRawParameters()[len(parameters)-1] == 0 {
+		t.Fatal("the best network shares memory with the trainer")
+	}
+}
+
+func TestEvolutionConfig(t *testing.T) {
+	trainer := createDummyNetworkTrainer()
+	if trainer.GetEvolutionConfig() != DefaultEvolutionConfig() {
+		t.Fatal("a new trainer should use the default evolution config")
+	}
+
+	badConfigs := []EvolutionConfig{
+		{StrengthOfEvolution: -1, PercentageOfChildrenToParents: 0.5, MaxNetworksSurvivorsWeight: 0.8},
+		{StrengthOfEvolution: 1, PercentageOfChildrenToParents: 0, MaxNetworksSurvivorsWeight: 0.8},
+		{StrengthOfEvolution: 1, PercentageOfChildrenToParents: 0.5, MaxNetworksSurvivorsWeight: 0},
+		{StrengthOfEvolution: 1, PercentageOfChildrenToParents: 0.5, MaxNetworksSurvivorsWeight: 1},
+	}
+	for i, config := range badConfigs {
+		if err := trainer.SetEvolutionConfig(config); err == nil {
+			t.Fatalf("bad evolution config %d should fail", i)
+		}
+	}
+	if trainer.GetEvolutionConfig() != DefaultEvolutionConfig() {
+		t.Fatal("a bad evolution config can't replace the old one")
+	}
+
+	config := EvolutionConfig{StrengthOfEvolution: 1, PercentageOfChildrenToParents: 2, FavourBestNetworksWhileMating: false, MaxNetworksSurvivorsWeight: 0.5}
+	if err := trainer.SetEvolutionConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(createTrainingData(30), 3, Evolution); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReproducibleTraining(t *testing.T) {
+	trainWithSeed := func(seed int64) Trainer {
+		var net network.Network
+		net.InitializeNetwork(rand.New(rand.NewSource(seed)), []int{3, 5, 2}, []string{"red", "notRed"}, nil, nil)
+		trainer := NewTrainer(net, 12, seed)
+		if err := trainer.Train(createTrainingData(40), 10, Evolution); err != nil {
+			t.Fatal(err)
+		}
+		if err := trainer.Train(createTrainingData(40), 3, BackPropagation); err != nil {
+			t.Fatal(err)
+		}
+		return trainer
+	}
+
+	first, second, other := trainWithSeed(3), trainWithSeed(3), trainWithSeed(4)
+	for i := range first.networks {
+		firstParameters, secondParameters := first.networks[i].GetRawParameters(), second.networks[i].GetRawParameters()
+		for j := range firstParameters {
+			if math.Float64bits(firstParameters[j]) != math.Float64bits(secondParameters[j]) {
+				t.Fatal("training with the same seed gave different networks")
+			}
+		}
+	}
+	if first.networks[0].GetCost() == other.networks[0].GetCost() {
+		t.Fatal("training with different seeds gave the same networks")
+	}
+}
+
+func BenchmarkEvolutionTraining(b *testing.B) {
+	var net network.Network
+	net.InitializeNetwork(newTestRandom(), []int{3, 32, 32, 2}, []string{"red", "notRed"}, nil, nil)
+	trainer := NewTrainer(net, 50, testSeed)
+	dataSets := createTrainingData(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := trainer.Train(dataSets, 1, Evolution); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestBatches(t *testing.T) {
+	trainer := createDummyNetworkTrainer()
+	trainer.trainDataSets = createTrainingData(10)
+
+	// batches of 4 have to cover the epoch as 4, 4, 2 or 4, 4 with the last one dropped
+	for _, dropLast := range []bool{false, true} {
+		for _, shuffle := range []bool{false, true} {
+			if err := trainer.SetBatchConfig(BatchConfig{BatchSize: 4, Shuffle: shuffle, DropLast: dropLast}); err != nil {
+				t.Fatal(err)
+			}
+			trainer.startEpoch()
+			var sizes []int
+			used := make(map[*float64]bool)
+			for batch, ok := trainer.nextBatch(4); ok; batch, ok = trainer.nextBatch(4) {
+				sizes = append(sizes, len(batch))
+				for _, data := range batch {
+					used[&data.GetInputs()[0]] = true
+				}
+			}
+			expectedSizes := "[4 4 2]"
+			if dropLast {
+				expectedSizes = "[4 4]"
+			}
+			if fmt.Sprint(sizes) != expectedSizes {
+				t.Fatal("wrong batch sizes: ", sizes)
+			}
+			if !dropLast && len(used) != len(trainer.trainDataSets) {
+				t.Fatal("every data set has to be used once in an epoch")
+			}
+		}
+	}
+
+	trainer.startEpoch()
+	shuffled := false
+	for i, index := range trainer.epochOrder {
+		shuffled = shuffled || i != index
+	}
+	if !shuffled {
+		t.Fatal("the epoch wasn't shuffled")
+	}
+
+	if err := trainer.SetBatchConfig(BatchConfig{BatchSize: -1}); err == nil {
+		t.Fatal("negative batch size got through")
+	}
+	if err := trainer.SetBatchConfig(BatchConfig{BatchSize: 20, DropLast: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(trainer.trainDataSets, 1, Evolution); err == nil {
+		t.Fatal("batch bigger than the data with the last one dropped got through")
+	}
+	if err := trainer.Train(nil, 1, Evolution); err == nil {
+		t.Fatal("training without data got through")
+	}
+}
+
+func TestEpochOverTrainCalls(t *testing.T) {
+	dataSets := createTrainingData(4)
+	trainer := createDummyNetworkTrainer()
+	if err := trainer.SetBatchConfig(BatchConfig{BatchSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+	epochEnds := 0
+	trainer.AddCallback(CallbackFuncs{EpochEnd: func(event TrainingEvent) error {
+		epochEnds++
+		return nil
+	}})
+
+	// every call takes the next batch instead of starting the epoch again
+	var positions []int
+	for i := 0; i < 3; i++ {
+		if err := trainer.Train(dataSets, 1, Evolution); err != nil {
+			t.Fatal(err)
+		}
+		positions = append(positions, trainer.epochPosition)
+	}
+	if fmt.Sprint(positions) != "[1 2 3]" || trainer.epoch != 0 {
+		t.Fatal("the epoch hasn't gone on over the calls: ", positions, trainer.epoch)
+	}
+
+	var buffer bytes.Buffer
+	if err := trainer.Checkpoint(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resumedTrainer, err := ResumeTrainer(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumedTrainer.epochPosition != 3 {
+		t.Fatal("the position in the epoch hasn't been restored: ", resumedTrainer.epochPosition)
+	}
+	for _, myTrainer := range []*Trainer{trainer, &resumedTrainer} {
+		if err := myTrainer.Train(dataSets, 1, Evolution); err != nil {
+			t.Fatal(err)
+		}
+	}
+	compareTrainers(t, trainer, &resumedTrainer, dataSets)
+	if trainer.epoch != 1 || resumedTrainer.epoch != 1 || epochEnds != 1 {
+		t.Fatal("the epoch hasn't ended: ", trainer.epoch, resumedTrainer.epoch, epochEnds)
+	}
+
+	// different data starts a new epoch
+	if err := trainer.Train(dataSets, 1, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(createTrainingData(4), 1, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if trainer.epochPosition != 1 {
+		t.Fatal("different data hasn't started a new epoch: ", trainer.epochPosition)
+	}
+}
+
+func TestMiniBatchTraining(t *testing.T) {
+	dataSets := createTrainingData(100)
+	for _, algorithm := range []Algorithm{Evolution, BackPropagation} {
+		var net network.Network
+		net.InitializeNetwork(newTestRandom(), []int{3, 3, 2}, []string{"red", "notRed"}, nil, nil)
+		trainer := NewTrainer(net, 10, testSeed)
+		if err := trainer.SetBatchConfig(BatchConfig{BatchSize: 16, Shuffle: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		calculateAverageCosts(&trainer.networks, dataSets, trainer.loss)
+		best := trainer.Best()
+		beforeCost := best.GetCost()
+		if err := trainer.Train(dataSets, 50, algorithm); err != nil {
+			t.Fatal(err)
+		}
+		best = trainer.Best()
+		if afterCost := best.GetCost(); afterCost >= beforeCost {
+			t.Fatal("mini-batch training hasn't decreased the cost: ", algorithm, beforeCost, afterCost)
+		}
+	}
+
+	// the batch of a single data set is the same as the default back propagation
+	trainers := make([]Trainer, 2)
+	for i := range trainers {
+		var net network.Network
+		net.InitializeNetwork(newTestRandom(), []int{3, 3, 2}, []string{"red", "notRed"}, nil, nil)
+		trainers[i] = NewTrainer(net, 1, testSeed)
+	}
+	if err := trainers[1].SetBatchConfig(BatchConfig{BatchSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for i := range trainers {
+		if err := trainers[i].Train(dataSets, 3, BackPropagation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	compareTrainers(t, &trainers[0], &trainers[1], dataSets)
+}
+
+func TestValidation(t *testing.T) {
+	dataSets := createTrainingData(100)
+	trainingData, validationData := dataSets[:80], dataSets[80:]
+
+	trainer := createDummyNetworkTrainer()
+	trainer.SetValidationData(validationData)
+	if err := trainer.Train(trainingData, 5, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(trainingData, 5, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	history := trainer.GetValidationHistory()
+	if len(history) != 10 || trainer.StoppedEarly() {
+		t.Fatal("validation hasn't been done after every generation: ", len(history))
+	}
+	for i, result := range history {
+		if result.Generation != i+1 || result.Cost <= 0 || result.Accuracy < 0 || result.Accuracy > 1 {
+			t.Fatal("wrong validation result: ", result)
+		}
+	}
+
+	// the last result has been measured on the first network which the back propagation trains
+	correct := 0
+	for _, data := range validationData {
+		if label, _ := trainer.networks[0].GetBestOutput(data.GetInputs()); label == data.GetExpOutput() {
+			correct++
+		}
+	}
+	if accuracy := float64(correct) / float64(len(validationData)); accuracy != history[9].Accuracy {
+		t.Fatal("wrong validation accuracy: ", accuracy, history[9].Accuracy)
+	}
+	if cost := trainer.networks[0].GetAverageCost(validationData, trainer.loss); cost != history[9].Cost {
+		t.Fatal("wrong validation cost: ", cost, history[9].Cost)
+	}
+}
+
+func TestEarlyStopping(t *testing.T) {
+	dataSets := createTrainingData(100)
+	trainingData, validationData := dataSets[:80], dataSets[80:]
+
+	trainer := createDummyNetworkTrainer()
+	if err := trainer.SetEarlyStopping(EarlyStoppingConfig{Patience: 0}); err == nil {
+		t.Fatal("patience of 0 got through")
+	}
+	if err := trainer.SetEarlyStopping(EarlyStoppingConfig{Patience: 1, MinDelta: -1}); err == nil {
+		t.Fatal("negative min delta got through")
+	}
+	// every generation after the first one is too small of an improvement
+	if err := trainer.SetEarlyStopping(EarlyStoppingConfig{Patience: 2, MinDelta: 1e9, RestoreBest: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(trainingData, 10, BackPropagation); err == nil {
+		t.Fatal("early stopping without validation data got through")
+	}
+
+	trainer.SetValidationData(validationData)
+	if err := trainer.Train(trainingData, 10, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	if !trainer.StoppedEarly() || trainer.GetGeneration() != 3 || len(trainer.GetValidationHistory()) != 3 {
+		t.Fatal("the training hasn't been stopped after the patience has run out: ", trainer.GetGeneration())
+	}
+
+	// the restored network is the one after the first generation
+	expectedTrainer := createDummyNetworkTrainer()
+	if err := expectedTrainer.Train(trainingData, 1, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(trainer.networks[0].GetRawParameters()) != fmt.Sprint(expectedTrainer.networks[0].GetRawParameters()) {
+		t.Fatal("the best network hasn't been restored")
+	}
+
+	trainer.DisableEarlyStopping()
+	if err := trainer.Train(trainingData, 10, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	if trainer.StoppedEarly() || trainer.GetGeneration() != 13 {
+		t.Fatal("the training has been stopped without early stopping")
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	dataSets := createTrainingData(20)
+	trainer := createDummyNetworkTrainer()
+	if err := trainer.SetBatchConfig(BatchConfig{BatchSize: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []EventType
+	var lastEvent TrainingEvent
+	record := func(event TrainingEvent) error {
+		types = append(types, event.Type)
+		lastEvent = event
+		if event.Type == GenerationEnd && (event.BestCost > event.AverageCost || event.AverageCost > event.WorstCost || event.BestCost <= 0) {
+			t.Fatal("wrong costs: ", event)
+		}
+		return nil
+	}
+	trainer.AddCallback(CallbackFuncs{GenerationStart: record, GenerationEnd: record, EpochEnd: record, TrainEnd: record})
+	events := make(chan TrainingEvent, 100)
+	trainer.SetEventChannel(events)
+
+	// 4 batches make an epoch
+	if err := trainer.Train(dataSets, 8, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	generation := []EventType{GenerationStart, GenerationEnd}
+	var expectedTypes []EventType
+	for i := 0; i < 8; i++ {
+		expectedTypes = append(expectedTypes, generation...)
+		if i%4 == 3 {
+			expectedTypes = append(expectedTypes, EpochEnd)
+		}
+	}
+	expectedTypes = append(expectedTypes, TrainEnd)
+	if fmt.Sprint(types) != fmt.Sprint(expectedTypes) {
+		t.Fatal("wrong events: ", types)
+	}
+	if lastEvent.Generation != 8 || lastEvent.Epoch != 2 || lastEvent.StoppedEarly || lastEvent.Algorithm != Evolution || lastEvent.Validation != nil {
+		t.Fatal("wrong training end event: ", lastEvent)
+	}
+	if len(events) != len(expectedTypes) {
+		t.Fatal("not all events have been sent to the channel: ", len(events))
+	}
+	for _, expectedType := range expectedTypes {
+		if event := <-events; event.Type != expectedType {
+			t.Fatal("wrong event in the channel: ", event)
+		}
+	}
+
+	// every generation of the back propagation is an epoch and measures only the trained network
+	trainer.SetEventChannel(nil)
+	trainer.SetValidationData(dataSets[:5])
+	types = nil
+	trainer.AddCallback(CallbackFuncs{GenerationEnd: func(event TrainingEvent) error {
+		if event.BestCost != event.WorstCost || event.Validation == nil || event.Validation.Generation != event.Generation {
+			t.Fatal("wrong back propagation event: ", event)
+		}
+		if event.Generation == 11 {
+			return ErrStopTraining
+		}
+		return nil
+	}})
+	if err := trainer.Train(dataSets, 5, BackPropagation); err != nil {
+		t.Fatal(err)
+	}
+	if !trainer.StoppedEarly() || trainer.GetGeneration() != 11 || !lastEvent.StoppedEarly || lastEvent.Epoch != 5 {
+		t.Fatal("the callback hasn't stopped the training: ", trainer.GetGeneration(), lastEvent)
+	}
+
+	callbackErr := errors.New("callback error")
+	trainer.AddCallback(CallbackFuncs{GenerationStart: func(event TrainingEvent) error {
+		return callbackErr
+	}})
+	if err := trainer.Train(dataSets, 5, BackPropagation); !errors.Is(err, callbackErr) {
+		t.Fatal("the callback's error hasn't been returned: ", err)
+	}
+}
+
+func TestTrainContext(t *testing.T) {
+	dataSets := createTrainingData(50)
+	for _, algorithm := range []Algorithm{Evolution, BackPropagation} {
+		trainer := createDummyNetworkTrainer()
+		ctx, cancel := context.WithCancel(context.Background())
+		var parameters []string
+		trainer.AddCallback(CallbackFuncs{
+			GenerationStart: func(event TrainingEvent) error {
+				// the generation is cancelled right after it has started
+				if event.Generation == 3 {
+					parameters = nil
+					for i := range trainer.networks {
+						parameters = append(parameters, fmt.Sprint(trainer.networks[i].GetRawParameters()))
+					}
+					cancel()
+				}
+				return nil
+			},
+		})
+
+		if err := trainer.TrainContext(ctx, dataSets, 10, algorithm); !errors.Is(err, context.Canceled) {
+			t.Fatal("the context's error hasn't been returned: ", err)
+		}
+		if trainer.GetGeneration() != 3 {
+			t.Fatal("the training hasn't been stopped: ", algorithm, trainer.GetGeneration())
+		}
+		// the cancelled generation has been dropped
+		for i := range trainer.networks {
+			if fmt.Sprint(trainer.networks[i].GetRawParameters()) != parameters[i] {
+				t.Fatal("the cancelled generation has changed the networks: ", algorithm)
+			}
+		}
+		best := trainer.Best()
+		if fmt.Sprint(best.GetRawParameters()) != parameters[0] {
+			t.Fatal("the best network found so far hasn't been returned: ", algorithm)
+		}
+
+		// the next training works normally
+		if err := trainer.Train(dataSets, 1, algorithm); err != nil || trainer.GetGeneration() != 4 {
+			t.Fatal("the training after the cancelled one has failed: ", err)
+		}
+	}
+
+	trainer := createDummyNetworkTrainer()
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if err := trainer.TrainContext(ctx, dataSets, 10, Evolution); !errors.Is(err, context.DeadlineExceeded) || trainer.GetGeneration() != 0 {
+		t.Fatal("the deadline hasn't stopped the training: ", err)
+	}
+
+	// the cost evaluation stops when the context is done
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := calculateAverageCostsContext(ctx, &trainer.networks, dataSets, trainer.loss); !errors.Is(err, context.Canceled) {
+		t.Fatal("the cost evaluation hasn't been cancelled: ", err)
+	}
+}
+
+// always chooses the best network
+type bestOnlySelection struct{}
+
+func (bestOnlySelection) Select(random *rand.Rand, costs []float64, amount int) []int {
+	return make([]int, amount)
+}
+
+func TestSelectors(t *testing.T) {
+	costs := make([]float64, 10)
+	for i := range costs {
+		costs[i] = float64(i+1) / 10
+	}
+	const amount = 10000
+	selectors := []Selector{
+		UniformSelection{},
+		GeometricSelection{Ratio: 0.8},
+		TournamentSelection{Size: 3},
+		RouletteWheelSelection{},
+		RankSelection{Pressure: 1.5},
+		StochasticUniversalSampling{},
+		TruncationSelection{Fraction: 0.3},
+	}
+	for _, selector := range selectors {
+		random := newTestRandom()
+		parents := selector.Select(random, costs, amount)
+		if len(parents) != amount {
+			t.Fatalf("%T has chosen wrong number of parents: %d", selector, len(parents))
+		}
+		counts := make([]int, len(costs))
+		for _, parent := range parents {
+			counts[parent]++
+		}
+		// all but the uniform selection favour better networks
+		if _, ok := selector.(UniformSelection); !ok && counts[0] <= counts[len(counts)-1]+amount/20 {
+			t.Fatalf("%T doesn't favour the best networks: %v", selector, counts)
+		}
+
+		switch selector.(type) {
+		case RouletteWheelSelection:
+			if counts[len(counts)-1] != 0 {
+				t.Fatal("the roulette wheel has chosen the worst network: ", counts)
+			}
+		case StochasticUniversalSampling:
+			// every network is chosen the expected number of times rounded down or up
+			fitness := getFitness(costs)
+			sum := 0.0
+			for _, value := range fitness {
+				sum += value
+			}
+			for i, count := range counts {
+				expected := fitness[i] / sum * amount
+				if float64(count) < math.Floor(expected) || float64(count) > math.Ceil(expected) {
+					t.Fatal("stochastic universal sampling isn't evenly spaced: ", counts)
+				}
+			}
+		case TruncationSelection:
+			if counts[2] == 0 || counts[3] != 0 {
+				t.Fatal("truncation has chosen the wrong networks: ", counts)
+			}
+		}
+
+		config, err := NewSelectionConfig(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newSelector, err := config.NewSelector(); err != nil || newSelector != selector {
+			t.Fatal("the selector hasn't been described correctly: ", config, err)
+		}
+	}
+
+	// all costs the same give every network the same fitness
+	for _, fitness := range getFitness([]float64{1, 1, 1}) {
+		if fitness != 1 {
+			t.Fatal("the same costs have different fitness")
+		}
+	}
+
+	badConfigs := []SelectionConfig{
+		{Type: "unknown"},
+		{Type: "geometric", Ratio: 1},
+		{Type: "tournament"},
+		{Type: "rank", Pressure: 2.5},
+		{Type: "truncation", Fraction: 0},
+	}
+	trainer := createDummyNetworkTrainer()
+	for _, config := range badConfigs {
+		if _, err := config.NewSelector(); err == nil {
+			t.Fatal("bad selection config got through: ", config)
+		}
+	}
+	if err := trainer.SetSelector(TruncationSelection{Fraction: 2}); err == nil {
+		t.Fatal("bad selector got through")
+	}
+	if _, ok := trainer.GetSelector().(GeometricSelection); !ok {
+		t.Fatal("the evolution config's selector isn't the default one")
+	}
+}
+
+func TestParentsAreDifferent(t *testing.T) {
+	trainer := createDummyNetworkTrainer()
+	costs := []float64{1, 2, 3}
+	for i := 0; i < 100; i++ {
+		if second := trainer.getSecondParent(bestOnlySelection{}, costs, 0, 0); second == 0 || second >= len(costs) {
+			t.Fatal("the same network has been chosen as both parents")
+		}
+	}
+	if second := trainer.getSecondParent(bestOnlySelection{}, costs[:1], 0, 0); second != 0 {
+		t.Fatal("a single network has to be both parents")
+	}
+
+	// selectors are used by the training and saved in checkpoints
+	dataSets := createTrainingData(30)
+	if err := trainer.SetSelector(TournamentSelection{Size: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(dataSets, 3, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := trainer.Checkpoint(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resumedTrainer, err := ResumeTrainer(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumedTrainer.GetSelector() != trainer.GetSelector() {
+		t.Fatal("the selector hasn't been restored: ", resumedTrainer.GetSelector())
+	}
+	if err := trainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedTrainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	compareTrainers(t, trainer, &resumedTrainer, dataSets)
+
+	if err := trainer.SetSelector(bestOnlySelection{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Checkpoint(&buffer); err == nil {
+		t.Fatal("custom selector has been saved")
+	}
+}
+
+func TestCrossovers(t *testing.T) {
+	trainer := createDummyNetworkTrainer()
+	layout := trainer.getGenomeLayout()
+	if trainer.genomeLayout == nil {
+		t.Fatal("the genome layout hasn't been kept by the trainer")
+	}
+	numberOfGenes := len(trainer.networks[0].GetRawParameters())
+	// every gene belongs to exactly one layer and one node
+	for _, blocks := range [][][]int{layout.Layers, layout.Nodes} {
+		seen := make([]bool, numberOfGenes)
+		for _, block := range blocks {
+			for _, index := range block {
+				if seen[index] {
+					t.Fatal("gene is in two blocks: ", index)
+				}
+				seen[index] = true
+			}
+		}
+		for index := range seen {
+			if !seen[index] {
+				t.Fatal("gene isn't in any block: ", index)
+			}
+		}
+	}
+	if len(layout.Layers) != 2 || len(layout.Nodes) != 9 {
+		t.Fatal("wrong number of layers or nodes: ", len(layout.Layers), len(layout.Nodes))
+	}
+
+	first, second := make([]float64, numberOfGenes), make([]float64, numberOfGenes)
+	for i := range first {
+		first[i], second[i] = 1, 2
+	}
+	// returns the number of times the child's genes switch from one value to another
+	countSwitches := func(child []float64) int {
+		switches := 0
+		for i := 1; i < len(child); i++ {
+			if child[i] != child[i-1] {
+				switches++
+			}
+		}
+		return switches
+	}
+	crossovers := []Crossover{
+		UniformCrossover{Bias: 0.5},
+		UniformCrossover{Bias: 0.9},
+		SinglePointCrossover{},
+		TwoPointCrossover{},
+		LayerCrossover{},
+		NodeCrossover{},
+		ArithmeticCrossover{Weight: 0.25},
+		BlendCrossover{Alpha: 0.5},
+		SimulatedBinaryCrossover{Eta: 1000},
+	}
+	random := newTestRandom()
+	for _, crossover := range crossovers {
+		child := make([]float64, numberOfGenes)
+		crossover.Crossover(random, layout, first, second, child)
+		for _, gene := range child {
+			switch crossover.(type) {
+			case UniformCrossover, SinglePointCrossover, TwoPointCrossover, LayerCrossover, NodeCrossover:
+				if gene != 1 && gene != 2 {
+					t.Fatalf("%T has created a new gene: %v", crossover, gene)
+				}
+			case ArithmeticCrossover:
+				if gene != 1.75 {
+					t.Fatal("arithmetic crossover has created a wrong gene: ", gene)
+				}
+			case BlendCrossover:
+				if gene < 0.5 || gene > 2.5 {
+					t.Fatal("blend crossover's gene is out of its range: ", gene)
+				}
+			case SimulatedBinaryCrossover:
+				// a big distribution index keeps the child close to one of its parents
+				if math.Abs(gene-1) > 0.05 && math.Abs(gene-2) > 0.05 {
+					t.Fatal("simulated binary crossover's gene is too far from the parents: ", gene)
+				}
+			}
+		}
+
+		switch crossover.(type) {
+		case SinglePointCrossover:
+			if child[0] != 1 || countSwitches(child) != 1 {
+				t.Fatal("single-point crossover hasn't been cut in one point: ", child)
+			}
+		case TwoPointCrossover:
+			if child[0] != 1 || countSwitches(child) > 2 {
+				t.Fatal("two-point crossover has been cut in more than two points: ", child)
+			}
+		case LayerCrossover, NodeCrossover:
+			blocks := layout.Layers
+			if _, ok := crossover.(NodeCrossover); ok {
+				blocks = layout.Nodes
+			}
+			for _, block := range blocks {
+				for _, index := range block {
+					if child[index] != child[block[0]] {
+						t.Fatalf("%T has mixed genes of a block", crossover)
+					}
+				}
+			}
+		}
+
+		config, err := NewCrossoverConfig(crossover)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newCrossover, err := config.NewCrossover(); err != nil || newCrossover != crossover {
+			t.Fatal("the crossover hasn't been described correctly: ", config, err)
+		}
+	}
+
+	badConfigs := []CrossoverConfig{
+		{Type: "unknown"},
+		{Type: "uniform", Bias: 1.5},
+		// without the parameters one of the parents would be copied
+		{Type: "uniform"},
+		{Type: "uniform", Bias: 1},
+		{Type: "arithmetic"},
+		{Type: "arithmetic", Weight: -0.5},
+		{Type: "blend", Alpha: -1},
+		{Type: "simulatedBinary", Eta: -1},
+	}
+	for _, config := range badConfigs {
+		if _, err := config.NewCrossover(); err == nil {
+			t.Fatal("bad crossover config got through: ", config)
+		}
+	}
+	if err := trainer.SetCrossover(BlendCrossover{Alpha: -1}); err == nil {
+		t.Fatal("bad crossover got through")
+	}
+	if trainer.GetCrossover() != (UniformCrossover{Bias: 0.5}) {
+		t.Fatal("the uniform crossover isn't the default one: ", trainer.GetCrossover())
+	}
+
+	// crossovers are used by the training and saved in checkpoints
+	dataSets := createTrainingData(30)
+	if err := trainer.SetCrossover(NodeCrossover{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := trainer.Train(dataSets, 3, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := trainer.Checkpoint(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resumedTrainer, err := ResumeTrainer(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumedTrainer.GetCrossover() != trainer.GetCrossover() {
+		t.Fatal("the crossover hasn't been restored: ", resumedTrainer.GetCrossover())
+	}
+	if err := trainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedTrainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	compareTrainers(t, trainer, &resumedTrainer, dataSets)
+}
+
+func TestMutators(t *testing.T) {
+	schedules := []struct {
+		schedule   DecaySchedule
+		generation int
+		factor     float64
+	}{
+		{DecaySchedule{}, 100, 1},
+		{DecaySchedule{Type: "exponential", Rate: 0.5}, 2, 0.25},
+		{DecaySchedule{Type: "exponential", Rate: 0.5, Min: 0.3}, 10, 0.3},
+		{DecaySchedule{Type: "step", Rate: 0.5, Steps: 3}, 5, 0.5},
+		{DecaySchedule{Type: "linear", Steps: 10, Min: 0.2}, 5, 0.6},
+		{DecaySchedule{Type: "linear", Steps: 10, Min: 0.2}, 20, 0.2},
+	}
+	for _, test := range schedules {
+		if factor := test.schedule.Factor(test.generation); math.Abs(factor-test.factor) > 1e-9 {
+			t.Fatal("wrong decay factor: ", test.schedule, factor)
+		}
+	}
+
+	trainer := createDummyNetworkTrainer()
+	parent := trainer.networks[0]
+	mutators := []Mutator{
+		UniformMutation{Strength: 0.5, Probability: 1},
+		UniformMutation{Strength: 0.5, Probability: 0.3, Decay: DecaySchedule{Type: "exponential", Rate: 0.5}},
+		GaussianMutation{Sigma: 0.1, Probability: 1},
+		SelfAdaptiveMutation{InitialSigma: 0.1, MinSigma: 0.05, Probability: 1},
+		ResetMutation{Range: 0.2, Probability: 0.5},
+	}
+	for _, mutator := range mutators {
+		random := newTestRandom()
+		child := parent.CopyNetwork()
+		counter := mutationCounter{}
+		counter.add(&child, mutator.Mutate(random, 1, &child))
+		statistics := counter.finish()
+		// the mutator counts the same changes which can be seen in the child
+		var changes ChildMutation
+		for i, gene := range child.GetRawParameters() {
+			changes.Add(gene - parent.GetRawParameters()[i])
+		}
+		if changes.MutatedGenes != int(statistics.MutatedFraction*float64(len(child.GetRawParameters()))+0.5) ||
+			math.Abs(changes.MaxChange-statistics.MaxChange) > 1e-9 {
+			t.Fatalf("%T has counted wrong changes: %+v, %+v", mutator, changes, statistics)
+		}
+		if statistics.Children != 1 || statistics.MutatedFraction == 0 {
+			t.Fatalf("%T hasn't mutated the child: %+v", mutator, statistics)
+		}
+
+		switch myMutator := mutator.(type) {
+		case UniformMutation:
+			// the strength is halved by the decay after the first generation
+			if statistics.MaxChange > myMutator.Strength*myMutator.Decay.Factor(1) {
+				t.Fatal("uniform mutation has changed a gene too much: ", statistics.MaxChange)
+			}
+			if math.Abs(statistics.MutatedFraction-myMutator.Probability) > 0.2 {
+				t.Fatal("uniform mutation hasn't followed its probability: ", statistics.MutatedFraction)
+			}
+		case GaussianMutation:
+			// the average absolute change of the normal distribution is σ * sqrt(2/π)
+			if math.Abs(statistics.AverageChange-myMutator.Sigma*math.Sqrt(2/math.Pi)) > 0.03 {
+				t.Fatal("gaussian mutation has a wrong spread: ", statistics.AverageChange)
+			}
+		case SelfAdaptiveMutation:
+			if child.GetMutationStrength() < myMutator.MinSigma || statistics.AverageStrength != child.GetMutationStrength() {
+				t.Fatal("self-adaptive mutation hasn't set the child's strength: ", child.GetMutationStrength())
+			}
+			// the child inherits its parents' strength
+			grandchild := createChildFromParents(random, UniformCrossover{Bias: 0.5}, newGenomeLayout(&child), child, parent)
+			if grandchild.GetMutationStrength() != child.GetMutationStrength() {
+				t.Fatal("the strength hasn't been inherited: ", grandchild.GetMutationStrength())
+			}
+		case ResetMutation:
+			for i, gene := range child.GetRawParameters() {
+				if gene != parent.GetRawParameters()[i] && math.Abs(gene) > myMutator.Range {
+					t.Fatal("reset mutation's gene is out of its range: ", gene)
+				}
+			}
+		}
+
+		config, err := NewMutationConfig(mutator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newMutator, err := config.NewMutator(); err != nil || newMutator != mutator {
+			t.Fatal("the mutator hasn't been described correctly: ", config, err)
+		}
+	}
+
+	badConfigs := []MutationConfig{
+		{Type: "unknown", Probability: 1},
+		{Type: "uniform", Strength: 1},
+		{Type: "uniform", Probability: 1, Strength: -1},
+		{Type: "gaussian", Probability: 1, Sigma: 1, Decay: &DecaySchedule{Type: "exponential", Rate: 1}},
+		{Type: "gaussian", Probability: 1, Sigma: 1, Decay: &DecaySchedule{Type: "step", Rate: 0.5}},
+		{Type: "gaussian", Probability: 1, Sigma: 1, Decay: &DecaySchedule{Type: "unknown"}},
+		{Type: "selfAdaptive", Probability: 1},
+		{Type: "reset", Probability: 1, Range: 1, Decay: &DecaySchedule{Type: "linear", Steps: 5}},
+	}
+	for _, config := range badConfigs {
+		if _, err := config.NewMutator(); err == nil {
+			t.Fatal("bad mutation config got through: ", config)
+		}
+	}
+	if err := trainer.SetMutator(GaussianMutation{Sigma: 1, Probability: 2}); err == nil {
+		t.Fatal("bad mutator got through")
+	}
+	if trainer.GetMutator() != (UniformMutation{Strength: trainer.evolution.StrengthOfEvolution, Probability: 1}) {
+		t.Fatal("the evolution config's mutation isn't the default one: ", trainer.GetMutator())
+	}
+
+	// mutators are used by the training, saved in checkpoints and their statistics are sent with events
+	dataSets := createTrainingData(30)
+	if err := trainer.SetMutator(SelfAdaptiveMutation{InitialSigma: 0.5, Probability: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	var mutationEvents int
+	trainer.AddCallback(CallbackFuncs{GenerationEnd: func(event TrainingEvent) error {
+		if event.Mutation != nil && event.Mutation.Generation == event.Generation {
+			mutationEvents++
+		}
+		return nil
+	}})
+	if err := trainer.Train(dataSets, 3, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	history := trainer.GetMutationHistory()
+	if len(history) != 3 || mutationEvents != 3 {
+		t.Fatal("wrong number of mutation statistics: ", len(history), mutationEvents)
+	}
+	for _, statistics := range history {
+		if statistics.Children != 10 || statistics.MutatedFraction == 0 || statistics.AverageStrength == 0 {
+			t.Fatalf("wrong mutation statistics: %+v", statistics)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := trainer.Checkpoint(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resumedTrainer, err := ResumeTrainer(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumedTrainer.GetMutator() != trainer.GetMutator() {
+		t.Fatal("the mutator hasn't been restored: ", resumedTrainer.GetMutator())
+	}
+	if err := trainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedTrainer.Train(dataSets, 2, Evolution); err != nil {
+		t.Fatal(err)
+	}
+	compareTrainers(t, trainer, &resumedTrainer, dataSets)
+}
@@ -0,0 +1,133 @@
+package training
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// The best network's cost and accuracy measured on the validation data after a generation
+type ValidationResult struct {
+	// the trainer's generation after which the result was measured
+	Generation int
+	Cost       float64
+	// the fraction of correct predictions, 0 for regression
+	Accuracy float64
+}
+
+// Determines when the training stops because the validation cost doesn't decrease anymore
+type EarlyStoppingConfig struct {
+	// the number of generations without an improvement after which the training stops
+	Patience int `json:"patience"`
+	// the minimal decrease of the validation cost counted as an improvement
+	MinDelta float64 `json:"minDelta"`
+	// at the end of the training the best network is replaced by the one with the lowest validation cost
+	RestoreBest bool `json:"restoreBest"`
+}
+
+// checks if all hyperparameters are in their ranges
+func (config EarlyStoppingConfig) Validate() error {
+	if config.Patience <= 0 {
+		return errors.New("patience has to be bigger than 0")
+	}
+	if config.MinDelta < 0 {
+		return errors.New("min delta can't be negative")
+	}
+	return nil
+}
+
+// the state of the early stopping during a single training
+type earlyStopping struct {
+	bestCost                      float64
+	bestNetwork                   *network.Network // kept only if it has to be restored
+	generationsWithoutImprovement int
+}
+
+func newEarlyStopping() earlyStopping {
+	return earlyStopping{bestCost: math.Inf(1)}
+}
+
+// Sets the data on which the best network is measured after every generation.
+// Nil data turns the validation off
+func (trainer *Trainer) SetValidationData(dataSets network.DataSets) {
+	trainer.validationDataSets = dataSets
+}
+
+// Sets which of the data sets given to the trainer's owner are held out of the training for the validation.
+// The trainer doesn't use it, but it is saved in checkpoints so the resumed training can hold out the same data sets
+func (trainer *Trainer) SetHeldOutData(heldOut []bool) {
+	trainer.heldOut = heldOut
+}
+
+// returns which of the owner's data sets are held out for the validation, as set by SetHeldOutData
+func (trainer *Trainer) GetHeldOutData() []bool {
+	return trainer.heldOut
+}
+
+// Sets when the training is stopped early. It requires the validation data
+func (trainer *Trainer) SetEarlyStopping(config EarlyStoppingConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	trainer.earlyStopping = &config
+	return nil
+}
+
+// turns the early stopping off
+func (trainer *Trainer) DisableEarlyStopping() {
+	trainer.earlyStopping = nil
+}
+
+// returns the early stopping configuration or false if it is turned off
+func (trainer *Trainer) GetEarlyStopping() (EarlyStoppingConfig, bool) {
+	if trainer.earlyStopping == nil {
+		return EarlyStoppingConfig{}, false
+	}
+	return *trainer.earlyStopping, true
+}
+
+// returns validation results of all generations trained with the validation data
+func (trainer *Trainer) GetValidationHistory() []ValidationResult {
+	return append([]ValidationResult(nil), trainer.validationHistory...)
+}
+
+// returns whether the last training was stopped early
+func (trainer *Trainer) StoppedEarly() bool {
+	return trainer.stoppedEarly
+}
+
+// Measures the current best network on the validation data and returns whether the training should stop.
+// After every generation the first network is the best one - it is trained by the back propagation
+// and the evolution leaves the networks sorted
+func (trainer *Trainer) validate(state *earlyStopping) bool {
+	net := &trainer.networks[0]
+	result := ValidationResult{
+		Generation: trainer.generation,
+		Cost:       net.GetAverageCost(trainer.validationDataSets, trainer.loss),
+		Accuracy:   net.GetAccuracy(trainer.validationDataSets),
+	}
+	trainer.validationHistory = append(trainer.validationHistory, result)
+	if trainer.earlyStopping == nil {
+		return false
+	}
+
+	if result.Cost < state.bestCost-trainer.earlyStopping.MinDelta {
+		state.bestCost = result.Cost
+		state.generationsWithoutImprovement = 0
+		if trainer.earlyStopping.RestoreBest {
+			bestNetwork := net.CopyNetwork()
+			state.bestNetwork = &bestNetwork
+		}
+		return false
+	}
+	state.generationsWithoutImprovement++
+	return state.generationsWithoutImprovement >= trainer.earlyStopping.Patience
+}
+
+// replaces the first network with the one which had the lowest validation cost if it has to be restored
+func (trainer *Trainer) restoreBest(state *earlyStopping) {
+	if trainer.earlyStopping != nil && trainer.earlyStopping.RestoreBest && state.bestNetwork != nil {
+		trainer.networks[0] = *state.bestNetwork
+	}
+}
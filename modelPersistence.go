@@ -0,0 +1,100 @@
+package NeuralNetwork
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+)
+
+// the version of the saved model's schema. It has to be increased with every incompatible change
+const modelFormatVersion = 1
+
+// The JSON schema of a saved model (version 1):
+//
+//	{
+//	  "version": 1,
+//	  "task": "classification" | "regression" | "multi-label classification",
+//	  "numberOfTrainingNetworks": 10,
+//	  "network": {
+//	    "layers": [
+//	      {"nodes": 3},
+//	      {"nodes": 2, "activation": "sigmoid", "biases": [0.1, -0.4], "weights": [[0.5, 1, -2], [0, 0.3, 0.7]]}
+//	    ],
+//	    "outputLabels": ["red", "notRed"]
+//	  }
+//	}
+//
+// weights[j][k] is the weight of the connection from the previous layer's node k to the layer's node j.
+// Regression models have no output labels
+type savedModel struct {
+	Version                  int              `json:"version"`
+	Task                     string           `json:"task"`
+	NumberOfTrainingNetworks int              `json:"numberOfTrainingNetworks"`
+	Network                  *network.Network `json:"network"`
+}
+
+// Writes the network's structure, labels, activations, weights and biases as JSON.
+// Training data and trainer's state aren't saved
+func (neuralNet *Network) Save(w io.Writer) error {
+	model := savedModel{
+		Version:                  modelFormatVersion,
+		Task:                     neuralNet.task.String(),
+		NumberOfTrainingNetworks: neuralNet.numberOfTrainingNetworks,
+		Network:                  &neuralNet.network,
+	}
+	return json.NewEncoder(w).Encode(model)
+}
+
+// Returns the network read from JSON written by Save, ready to be used or trained further
+func Load(r io.Reader) (*Network, error) {
+	var model savedModel
+	if err := json.NewDecoder(r).Decode(&model); err != nil {
+		return nil, err
+	}
+	if model.Version != modelFormatVersion {
+		return nil, fmt.Errorf("unsupported model version: %d", model.Version)
+	}
+	if model.Network == nil {
+		return nil, errors.New("the model has no network")
+	}
+
+	return newLoadedNeuralNetwork(model.Task, model.NumberOfTrainingNetworks, *model.Network)
+}
+
+// returns the neural network made of loaded parts after checking if they are correct
+func newLoadedNeuralNetwork(taskName string, numberOfTrainingNetworks int, net network.Network) (*Network, error) {
+	myTask, err := parseTask(taskName)
+	if err != nil {
+		return nil, err
+	}
+	nodesPerLayer := net.GetNetworkStructure()
+	if err := validateNetworkInit(numberOfTrainingNetworks, nodesPerLayer, net.GetActivations()); err != nil {
+		return nil, err
+	}
+	if myTask == regression && net.GetOutputLabels() != nil {
+		return nil, errors.New("regression model can't have output labels")
+	}
+	if myTask != regression {
+		if err := validateOutputLabels(nodesPerLayer, net.GetOutputLabels()); err != nil {
+			return nil, err
+		}
+	}
+
+	neuralNet := Network{
+		network:                  net,
+		numberOfTrainingNetworks: numberOfTrainingNetworks,
+		task:                     myTask,
+		loss:                     MeanSquaredError{},
+		ensembleSize:             1,
+		random:                   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	if myTask == multiLabel {
+		neuralNet.loss = BinaryCrossEntropy{}
+	}
+	return &neuralNet, nil
+}
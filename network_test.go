@@ -1,8 +1,18 @@
 package NeuralNetwork
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
 )
 
 // used for presenting the network for the developer
@@ -66,6 +76,41 @@ func TestBadInputData(t *testing.T) {
 	}
 }
 
+func TestActivations(t *testing.T) {
+	goodActivations := [][]Activation{
+		{},
+		{ReLU, Softmax},
+		{Tanh, Linear},
+		{LeakyReLU, Sigmoid},
+	}
+	for _, activations := range goodActivations {
+		myNetwork, err := NewNeuralNetwork(1, []int{3, 5, 2}, []string{"1", "2"}, activations...)
+		if err != nil {
+			t.Fatal(activations, err)
+		}
+		outputs, err := myNetwork.GetOutputMap([]float64{0.5, 0.1, 1})
+		if err != nil {
+			t.Fatal(activations, err)
+		}
+		if len(activations) != 0 && activations[1] == Softmax && math.Abs(outputs["1"]+outputs["2"]-1) > 1e-9 {
+			t.Fatal("softmax outputs don't sum up to 1: ", outputs)
+		}
+	}
+
+	badActivations := [][]Activation{
+		{ReLU},
+		{ReLU, ReLU, ReLU},
+		{ReLU, Activation(-1)},
+		{Activation(100), Sigmoid},
+	}
+	for _, activations := range badActivations {
+		_, err := NewNeuralNetwork(1, []int{3, 5, 2}, []string{"1", "2"}, activations...)
+		if err == nil {
+			t.Fatal("bad activations got through: ", activations)
+		}
+	}
+}
+
 ///////////////////////////////////////////////////////
 ////			Calculating output tests		   ////
 ///////////////////////////////////////////////////////
@@ -244,9 +289,1567 @@ func TestCalculatingCosts(t *testing.T) {
 			t.Fatal(myData, err)
 		}
 
-		myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData)
+		myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
 		if myNetwork.network.GetCost() < 0 {
 			t.Fatal("calculated cost is incorect: ", myNetwork.network.GetCost(), myData)
 		}
 	}
 }
+
+/////////////////////////////////////////////////////////////
+////			    	Training Tests				     ////
+/////////////////////////////////////////////////////////////
+
+func TestTrainingAlgorithms(t *testing.T) {
+	structureData := testStructureData{[]int{3, 5, 2}, []string{"1", "2"}}
+	myNetwork, err := NewNeuralNetwork(10, structureData.nodesPerLayer, structureData.outputLabels)
+	if err != nil {
+		t.Fatal(structureData, err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	beforeCost := myNetwork.network.GetCost()
+	if err := myNetwork.TrainWithAlgorithm(100, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	if myNetwork.network.GetCost() >= beforeCost {
+		t.Fatal("back propagation hasn't changed the network: ", beforeCost, myNetwork.network.GetCost())
+	}
+
+	if err := myNetwork.Train(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(5, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(5, -1); err == nil {
+		t.Fatal("unknown training algorithm got through")
+	}
+}
+
+func TestSettingOptimizer(t *testing.T) {
+	myNetwork, err := NewNeuralNetwork(1, []int{3, 5, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetOptimizer(nil); err == nil {
+		t.Fatal("nil optimizer got through")
+	}
+	if err := myNetwork.SetOptimizer(training.NewAdam(0.01, 0.9, 0.999)); err != nil {
+		t.Fatal(err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(5, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetOptimizer(training.NewSGD(0.1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(5, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSettingLoss(t *testing.T) {
+	myNetwork, err := NewNeuralNetwork(1, []int{3, 5, 2}, []string{"1", "2"}, ReLU, Softmax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goodLosses := []Loss{MeanSquaredError{}, BinaryCrossEntropy{}, CategoricalCrossEntropy{}, Hinge{}, Huber{Delta: 1}}
+	for _, loss := range goodLosses {
+		if err := myNetwork.SetLoss(loss); err != nil {
+			t.Fatal(loss, err)
+		}
+		if err := myNetwork.TrainWithAlgorithm(2, BackPropagationTraining); err != nil {
+			t.Fatal(loss, err)
+		}
+		myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, loss)
+		if myNetwork.network.GetCost() < 0 {
+			t.Fatal("calculated cost is incorect: ", myNetwork.network.GetCost(), loss)
+		}
+	}
+
+	if err := myNetwork.SetLoss(nil); err == nil {
+		t.Fatal("nil loss got through")
+	}
+	if err := myNetwork.SetLoss(Huber{}); err == nil {
+		t.Fatal("huber without delta got through")
+	}
+	sigmoidNetwork, err := NewNeuralNetwork(1, []int{3, 5, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sigmoidNetwork.SetLoss(CategoricalCrossEntropy{}); err == nil {
+		t.Fatal("categorical cross entropy without softmax got through")
+	}
+}
+
+/////////////////////////////////////////////////////////////
+////			    	Regression Tests				 ////
+/////////////////////////////////////////////////////////////
+
+func TestRegression(t *testing.T) {
+	myNetwork, err := NewRegressionNetwork(1, []int{2, 6, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activations := myNetwork.network.GetActivations(); activations[len(activations)-1] != Linear {
+		t.Fatal("regression output layer isn't linear by default: ", activations)
+	}
+
+	// the network has to learn the average of two inputs
+	var inputs, targets [][]float64
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 10; j++ {
+			inputs = append(inputs, []float64{float64(i) / 10, float64(j) / 10})
+			targets = append(targets, []float64{float64(i+j) / 20})
+		}
+	}
+	if err := myNetwork.LoadRegressionTrainingData(inputs, targets); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetOptimizer(training.NewSGD(0.05)); err != nil {
+		t.Fatal(err)
+	}
+
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	beforeCost := myNetwork.network.GetCost()
+	if err := myNetwork.TrainWithAlgorithm(30, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	if myNetwork.network.GetCost() >= beforeCost {
+		t.Fatal("regression cost hasn't decreased: ", beforeCost, myNetwork.network.GetCost())
+	}
+
+	outputs, err := myNetwork.Predict([]float64{0.2, 0.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 1 {
+		t.Fatal("wrong number of predicted values: ", outputs)
+	}
+}
+
+func TestRegressionBadData(t *testing.T) {
+	myNetwork, err := NewRegressionNetwork(1, []int{2, 3, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type data struct {
+		input  [][]float64
+		target [][]float64
+	}
+	badData := []data{
+		{[][]float64{{0.5, 0.5}}, [][]float64{{1}}},
+		{[][]float64{{0.5, 0.5}}, [][]float64{{1, 2, 3}}},
+		{[][]float64{{0.5, 0.5}, {0.1, 0.1}}, [][]float64{{1, 2}}},
+		{[][]float64{{0.5, 5}}, [][]float64{{1, 2}}},
+	}
+	for _, myData := range badData {
+		if err := myNetwork.LoadRegressionTrainingData(myData.input, myData.target); err == nil {
+			t.Fatal("bad data got through: ", myData)
+		}
+	}
+	if err := myNetwork.AddSingleRegressionTrainingData([]float64{0.5, 0.5}, []float64{-10, 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := myNetwork.GetNetworkResult([]float64{0.5, 0.5}); err == nil {
+		t.Fatal("regression network returned a label")
+	}
+	if _, err := myNetwork.GetOutputMap([]float64{0.5, 0.5}); err == nil {
+		t.Fatal("regression network returned an output map")
+	}
+	if err := myNetwork.AddSingleTrainingData([]float64{0.5, 0.5}, ""); err == nil {
+		t.Fatal("regression network accepted classification data")
+	}
+
+	classifier, err := NewNeuralNetwork(1, []int{2, 3, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := classifier.AddSingleRegressionTrainingData([]float64{0.5, 0.5}, []float64{1, 2}); err == nil {
+		t.Fatal("classification network accepted regression data")
+	}
+}
+
+/////////////////////////////////////////////////////////////
+////			    	Multi-Label Tests				 ////
+/////////////////////////////////////////////////////////////
+
+func TestMultiLabel(t *testing.T) {
+	labels := []string{"red", "green", "blue"}
+	myNetwork, err := NewMultiLabelNetwork(1, []int{3, 6, 3}, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every color belongs to labels of all channels which are brighter than a half
+	var inputs [][]float64
+	var outputs [][]string
+	for i := 0; i < 100; i++ {
+		input := []float64{rand.Float64(), rand.Float64(), rand.Float64()}
+		output := []string{}
+		for j, value := range input {
+			if value > 0.5 {
+				output = append(output, labels[j])
+			}
+		}
+		inputs = append(inputs, input)
+		outputs = append(outputs, output)
+	}
+	if err := myNetwork.LoadMultiLabelTrainingData(inputs, outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	beforeCost := myNetwork.network.GetCost()
+	if err := myNetwork.TrainWithAlgorithm(50, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	if myNetwork.network.GetCost() >= beforeCost {
+		t.Fatal("multi-label cost hasn't decreased: ", beforeCost, myNetwork.network.GetCost())
+	}
+
+	allLabels, err := myNetwork.GetLabelsAboveThreshold([]float64{0.5, 0.5, 0.5}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allLabels) != len(labels) {
+		t.Fatal("not all labels are above the lowest threshold: ", allLabels)
+	}
+	noLabels, err := myNetwork.GetLabelsAboveThreshold([]float64{0.5, 0.5, 0.5}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(noLabels) != 0 {
+		t.Fatal("labels are above the highest threshold: ", noLabels)
+	}
+}
+
+func TestMultiLabelBadData(t *testing.T) {
+	myNetwork, err := NewMultiLabelNetwork(1, []int{3, 6, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type data struct {
+		input  [][]float64
+		output [][]string
+	}
+	goodData := []data{
+		{[][]float64{{0.5, 0.5, 0.5}}, [][]string{{"1", "2"}}},
+		{[][]float64{{0.5, 0.5, 0.5}, {0, 0, 0}}, [][]string{{}, {"2"}}},
+	}
+	for _, myData := range goodData {
+		if err := myNetwork.LoadMultiLabelTrainingData(myData.input, myData.output); err != nil {
+			t.Fatal(myData, err)
+		}
+	}
+	badData := []data{
+		{[][]float64{{0.5, 0.5, 0.5}}, [][]string{{"1", "3"}}},
+		{[][]float64{{0.5, 0.5, 0.5}, {0, 0, 0}}, [][]string{{"1"}}},
+		{[][]float64{{0.5, 0.5, 2}}, [][]string{{"1"}}},
+	}
+	for _, myData := range badData {
+		if err := myNetwork.LoadMultiLabelTrainingData(myData.input, myData.output); err == nil {
+			t.Fatal("bad data got through: ", myData)
+		}
+	}
+
+	if err := myNetwork.AddSingleTrainingData([]float64{0.5, 0.5, 0.5}, "1"); err == nil {
+		t.Fatal("multi-label network accepted single label data")
+	}
+	if _, err := myNetwork.GetLabelsAboveThreshold([]float64{0.5, 0.5}, 0.5); err == nil {
+		t.Fatal("bad input data got through")
+	}
+}
+
+/////////////////////////////////////////////////////////////
+////			    	Persistence Tests				 ////
+/////////////////////////////////////////////////////////////
+
+// checks if both networks have the same structure and give the same outputs
+func compareNetworks(t *testing.T, first, second *Network) {
+	if first.task != second.task || first.numberOfTrainingNetworks != second.numberOfTrainingNetworks {
+		t.Fatal("networks' settings are different")
+	}
+	if fmt.Sprint(first.network.GetNetworkStructure(), first.network.GetActivations(), first.network.GetOutputLabels()) !=
+		fmt.Sprint(second.network.GetNetworkStructure(), second.network.GetActivations(), second.network.GetOutputLabels()) {
+		t.Fatal("networks' structures are different")
+	}
+
+	input := make([]float64, first.NumberOfInputNodes())
+	for i := range input {
+		input[i] = rand.Float64()
+	}
+	firstOutputs, _ := first.Predict(input)
+	secondOutputs, _ := second.Predict(input)
+	for i := range firstOutputs {
+		if firstOutputs[i] != secondOutputs[i] {
+			t.Fatal("networks give different outputs: ", firstOutputs, secondOutputs)
+		}
+	}
+}
+
+func createNetworksOfAllTasks(t *testing.T) []*Network {
+	classifier, err := NewNeuralNetwork(7, []int{3, 5, 4, 2}, []string{"1", "2"}, ReLU, Tanh, Softmax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regressor, err := NewRegressionNetwork(3, []int{2, 4, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	multiLabelNetwork, err := NewMultiLabelNetwork(1, []int{4, 2}, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*Network{classifier, regressor, multiLabelNetwork}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	for _, myNetwork := range createNetworksOfAllTasks(t) {
+		var buffer bytes.Buffer
+		if err := myNetwork.Save(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		loadedNetwork, err := Load(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareNetworks(t, myNetwork, loadedNetwork)
+	}
+}
+
+func TestLoadingBadModels(t *testing.T) {
+	const goodNetwork = `{"layers":[{"nodes":2},{"nodes":1,"activation":"linear","biases":[0],"weights":[[1,2]]}],"outputLabels":null}`
+	goodModels := []string{
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":` + goodNetwork + `}`,
+	}
+	for _, model := range goodModels {
+		if _, err := Load(strings.NewReader(model)); err != nil {
+			t.Fatal(model, err)
+		}
+	}
+
+	badModels := []string{
+		``,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":` + goodNetwork,
+		`{"version":2,"task":"regression","numberOfTrainingNetworks":1,"network":` + goodNetwork + `}`,
+		`{"version":1,"task":"painting","numberOfTrainingNetworks":1,"network":` + goodNetwork + `}`,
+		`{"version":1,"task":"classification","numberOfTrainingNetworks":1,"network":` + goodNetwork + `}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":0,"network":` + goodNetwork + `}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":{"layers":[]}}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":{"layers":[{"nodes":2},{"nodes":1,"activation":"magic","biases":[0],"weights":[[1,2]]}]}}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":{"layers":[{"nodes":2},{"nodes":1,"biases":[0],"weights":[[1,2]]}]}}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":{"layers":[{"nodes":2},{"nodes":1,"activation":"linear","biases":[0,1],"weights":[[1,2]]}]}}`,
+		`{"version":1,"task":"regression","numberOfTrainingNetworks":1,"network":{"layers":[{"nodes":2},{"nodes":1,"activation":"linear","biases":[0],"weights":[[1]]}]}}`,
+	}
+	for _, model := range badModels {
+		if _, err := Load(strings.NewReader(model)); err == nil {
+			t.Fatal("bad model got through: ", model)
+		}
+	}
+}
+
+func TestBinarySaveAndLoad(t *testing.T) {
+	for _, myNetwork := range createNetworksOfAllTasks(t) {
+		var buffer bytes.Buffer
+		if err := myNetwork.SaveBinary(&buffer, Float64Precision); err != nil {
+			t.Fatal(err)
+		}
+		loadedNetwork, err := LoadBinary(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareNetworks(t, myNetwork, loadedNetwork)
+
+		var float32Buffer, jsonBuffer bytes.Buffer
+		buffer.Reset()
+		myNetwork.SaveBinary(&buffer, Float64Precision)
+		myNetwork.Save(&jsonBuffer)
+		if err := myNetwork.SaveBinary(&float32Buffer, Float32Precision); err != nil {
+			t.Fatal(err)
+		}
+		if float32Buffer.Len() >= buffer.Len() || buffer.Len() >= jsonBuffer.Len() {
+			t.Fatal("binary models aren't smaller: ", float32Buffer.Len(), buffer.Len(), jsonBuffer.Len())
+		}
+		float32Network, err := LoadBinary(&float32Buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := make([]float64, myNetwork.NumberOfInputNodes())
+		outputs, _ := myNetwork.Predict(input)
+		float32Outputs, _ := float32Network.Predict(input)
+		for i := range outputs {
+			if math.Abs(outputs[i]-float32Outputs[i]) > 1e-5 {
+				t.Fatal("float32 network's outputs are too different: ", outputs, float32Outputs)
+			}
+		}
+	}
+}
+
+func TestLoadingBadBinaryModels(t *testing.T) {
+	myNetwork, err := NewNeuralNetwork(2, []int{3, 4, 2}, []string{"first", "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := myNetwork.SaveBinary(&buffer, Float64Precision); err != nil {
+		t.Fatal(err)
+	}
+	model := buffer.Bytes()
+
+	for i := 0; i < len(model); i++ {
+		if _, err := LoadBinary(bytes.NewReader(model[:i])); !errors.Is(err, ErrTruncatedModel) {
+			t.Fatal("truncated model to ", i, " bytes returned wrong error: ", err)
+		}
+	}
+
+	// a flipped bit is found by the magic (the first 4 bytes), the version (the next 2) or the checksum
+	for i := 0; i < len(model); i++ {
+		for bit := 0; bit < 8; bit++ {
+			corrupted := append([]byte{}, model...)
+			corrupted[i] ^= 1 << bit
+			expectedErr := ErrCorruptedModel
+			if i < 4 {
+				expectedErr = ErrNotBinaryModel
+			} else if i < 6 {
+				expectedErr = ErrUnsupportedModelVersion
+			}
+			if _, err := LoadBinary(bytes.NewReader(corrupted)); !errors.Is(err, expectedErr) {
+				t.Fatal("flipped bit ", bit, " of byte ", i, " returned wrong error: ", err)
+			}
+		}
+	}
+
+	wrongMagic := append([]byte("JSON"), model[4:]...)
+	if _, err := LoadBinary(bytes.NewReader(wrongMagic)); !errors.Is(err, ErrNotBinaryModel) {
+		t.Fatal("wrong magic returned wrong error: ", err)
+	}
+	newerVersion := append([]byte{}, model...)
+	newerVersion[4] = 3
+	if _, err := LoadBinary(bytes.NewReader(newerVersion)); !errors.Is(err, ErrUnsupportedModelVersion) {
+		t.Fatal("newer version returned wrong error: ", err)
+	}
+	lastParameter := append([]byte{}, model...)
+	lastParameter[len(model)-5] ^= 0x01
+	if _, err := LoadBinary(bytes.NewReader(lastParameter)); !errors.Is(err, ErrCorruptedModel) {
+		t.Fatal("changed parameter returned wrong error: ", err)
+	}
+}
+
+func TestResumingTrainer(t *testing.T) {
+	myNetwork, err := NewNeuralNetwork(5, []int{3, 4, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := myNetwork.CheckpointTrainer(&buffer); err == nil {
+		t.Fatal("not trained network has saved its trainer")
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.CheckpointTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := buffer.String()
+
+	if err := myNetwork.ResumeTrainer(strings.NewReader(checkpoint)); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+
+	otherNetwork, err := NewNeuralNetwork(5, []int{3, 5, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherNetwork.ResumeTrainer(strings.NewReader(checkpoint)); err == nil {
+		t.Fatal("trainer of a different network got through")
+	}
+	if _, err := New(WithLayers(3, 4, 2), WithLabels("cat", "dog"), WithTrainer(myNetwork.trainer)); err == nil {
+		t.Fatal("trainer of a network with different labels got through")
+	}
+	if _, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithActivation(ReLU, Softmax), WithTrainer(myNetwork.trainer)); err == nil {
+		t.Fatal("trainer of a network with different activations got through")
+	}
+
+	// labels joined with spaces look the same, but they aren't
+	spacedNetwork, err := New(WithLayers(3, 4, 2), WithLabels("a b", "c"), WithPopulationSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := spacedNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"a b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := spacedNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := spacedNetwork.CheckpointTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	otherSpacedNetwork, err := New(WithLayers(3, 4, 2), WithLabels("a", "b c"), WithPopulationSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherSpacedNetwork.ResumeTrainer(&buffer); err == nil {
+		t.Fatal("trainer of a network with different labels got through")
+	}
+}
+
+func TestAdoptingBestNetwork(t *testing.T) {
+	myNetwork, err := NewNeuralNetwork(10, []int{3, 4, 2}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}, {0.3, 0.9, 0.1}}, []string{"1", "2", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	myNetwork.network.CalculateCost(&sync.Mutex{}, myNetwork.trainingData, myNetwork.loss)
+	beforeCost := myNetwork.network.GetCost()
+
+	if err := myNetwork.Train(20); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.GetTrainingCost() <= 0 || myNetwork.GetTrainingCost() > beforeCost {
+		t.Fatal("the best network's cost is incorrect: ", myNetwork.GetTrainingCost(), beforeCost)
+	}
+	best := myNetwork.trainer.Best()
+	outputs, _ := myNetwork.Predict([]float64{0.1, 0.2, 0.3})
+	bestOutputs := best.GetOutputs([]float64{0.1, 0.2, 0.3})
+	for i := range outputs {
+		if outputs[i] != bestOutputs[i] {
+			t.Fatal("the best network hasn't been adopted: ", outputs, bestOutputs)
+		}
+	}
+
+	if err := myNetwork.SetEnsembleSize(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+	ensemble := myNetwork.trainer.BestNetworks(3)
+	outputs, _ = myNetwork.Predict([]float64{0.1, 0.2, 0.3})
+	for i := range outputs {
+		average := 0.0
+		for _, net := range ensemble {
+			average += net.GetOutputs([]float64{0.1, 0.2, 0.3})[i] / 3
+		}
+		if math.Abs(outputs[i]-average) > 1e-12 {
+			t.Fatal("ensemble's outputs aren't averaged: ", outputs[i], average)
+		}
+	}
+
+	for _, size := range []int{0, -1, 11} {
+		if err := myNetwork.SetEnsembleSize(size); err == nil {
+			t.Fatal("bad ensemble size got through: ", size)
+		}
+	}
+}
+
+func TestNetworkFromConfig(t *testing.T) {
+	path := t.TempDir() + "/config.json"
+	config := `{
+		"task": "classification",
+		"layers": [3, 5, 2],
+		"outputLabels": ["a", "b"],
+		"activations": ["relu", "softmax"],
+		"initializers": [{"type": "heNormal"}, {"type": "normal", "stdDev": 0.1, "zeroBias": true}],
+		"numberOfTrainingNetworks": 4,
+		"ensembleSize": 2,
+		"algorithm": "backPropagation",
+		"loss": {"type": "categoricalCrossEntropy"},
+		"optimizer": {"type": "adam", "learningRate": 0.01, "beta1": 0.9, "beta2": 0.999},
+		"evolution": {"strengthOfEvolution": 2, "percentageOfChildrenToParents": 1, "favourBestNetworksWhileMating": false, "maxNetworksSurvivorsWeight": 0.5},
+		"selection": {"type": "tournament", "tournamentSize": 2},
+		"crossover": {"type": "simulatedBinary", "eta": 2},
+		"mutation": {"type": "uniform", "strength": 1, "probability": 0.5, "decay": {"type": "step", "rate": 0.5, "steps": 2}},
+		"batch": {"batchSize": 2, "shuffle": true},
+		"validationSplit": 0.5,
+		"earlyStopping": {"patience": 3, "minDelta": 0.001, "restoreBest": true}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	myNetwork, err := NewNeuralNetworkFromConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(myNetwork.network.GetNetworkStructure()) != "[3 5 2]" || fmt.Sprint(myNetwork.network.GetActivations()) != "[relu softmax]" {
+		t.Fatal("the network's structure isn't the configured one")
+	}
+	if fmt.Sprint(myNetwork.network.GetInitializers()) != fmt.Sprint([]Initializer{HeNormal{}, ZeroBias{Initializer: Normal{StdDev: 0.1}}}) {
+		t.Fatal("the network's initializers aren't the configured ones: ", myNetwork.network.GetInitializers())
+	}
+	if myNetwork.algorithm != BackPropagationTraining || myNetwork.ensembleSize != 2 {
+		t.Fatal("the algorithm or ensemble size isn't the configured one")
+	}
+	if _, ok := myNetwork.loss.(CategoricalCrossEntropy); !ok {
+		t.Fatal("the loss isn't the configured one")
+	}
+	if adam, ok := myNetwork.optimizer.(*training.Adam); !ok || adam.LearningRate != 0.01 {
+		t.Fatal("the optimizer isn't the configured one")
+	}
+
+	if err := myNetwork.LoadTrainingData([][]float64{{0, 0.5, 1}, {1, 0.5, 0}}, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(5); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.trainer.GetEvolutionConfig() != *myNetwork.evolution || myNetwork.evolution.StrengthOfEvolution != 2 {
+		t.Fatal("the trainer didn't get the configured evolution hyperparameters")
+	}
+	if selector, ok := myNetwork.trainer.GetSelector().(training.TournamentSelection); !ok || selector.Size != 2 {
+		t.Fatal("the trainer didn't get the configured selection")
+	}
+	if myNetwork.trainer.GetCrossover() != (training.SimulatedBinaryCrossover{Eta: 2}) {
+		t.Fatal("the trainer didn't get the configured crossover")
+	}
+	expectedMutator := training.UniformMutation{Strength: 1, Probability: 0.5, Decay: training.DecaySchedule{Type: "step", Rate: 0.5, Steps: 2}}
+	if myNetwork.trainer.GetMutator() != expectedMutator {
+		t.Fatal("the trainer didn't get the configured mutation")
+	}
+	if batch := myNetwork.trainer.GetBatchConfig(); batch.BatchSize != 2 || !batch.Shuffle || batch.DropLast {
+		t.Fatal("the trainer didn't get the configured batches")
+	}
+	if earlyStopping, ok := myNetwork.trainer.GetEarlyStopping(); !ok || earlyStopping.Patience != 3 || myNetwork.validationSplit != 0.5 {
+		t.Fatal("the trainer didn't get the configured validation")
+	}
+
+	if _, err := NewNeuralNetworkFromConfig(t.TempDir() + "/missing.json"); err == nil {
+		t.Fatal("a missing configuration file should fail")
+	}
+
+	// omitted evolution hyperparameters keep their default values
+	partialConfig, err := LoadConfig(strings.NewReader(`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "evolution": {"strengthOfEvolution": 2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedEvolution := training.DefaultEvolutionConfig()
+	expectedEvolution.StrengthOfEvolution = 2
+	if *partialConfig.Evolution != expectedEvolution {
+		t.Fatal("omitted evolution hyperparameters don't have their default values: ", *partialConfig.Evolution)
+	}
+}
+
+func TestBadConfigs(t *testing.T) {
+	badConfigs := []string{
+		``,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "unknown": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1} {}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 0}`,
+		`{"layers": [2, 0], "outputLabels": ["a"], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a", "b"], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "activations": ["unknown"], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "activations": ["relu", "relu"], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "initializers": [{"type": "unknown"}], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "initializers": [{"type": "uniform"}], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "initializers": [{"type": "heUniform"}, {"type": "heUniform"}], "numberOfTrainingNetworks": 1}`,
+		`{"task": "unknown", "layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1}`,
+		`{"task": "regression", "layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "ensembleSize": 2}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "algorithm": "unknown"}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "loss": {"type": "unknown"}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "loss": {"type": "huber"}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "loss": {"type": "categoricalCrossEntropy"}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "optimizer": {"type": "sgd"}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "optimizer": {"type": "momentum", "learningRate": 0.1, "momentum": 1}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "optimizer": {"type": "adam", "learningRate": 0.1, "beta1": -0.1, "beta2": 0.9}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "optimizer": {"type": "sgd", "learningRate": 0.1, "unknown": 1}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "evolution": {"strengthOfEvolution": 1, "percentageOfChildrenToParents": 0, "maxNetworksSurvivorsWeight": 0.5}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "evolution": {"strengthOfEvolution": 1, "percentageOfChildrenToParents": 1, "maxNetworksSurvivorsWeight": 1}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "evolution": {"strengthOfEvolution": 1, "strength": 2}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "batch": {"batchSize": -1}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "selection": {"type": "rank", "pressure": 3}}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "validationSplit": 1}`,
+		`{"layers": [2, 1], "outputLabels": ["a"], "numberOfTrainingNetworks": 1, "earlyStopping": {"patience": 0}}`,
+	}
+	for i, badConfig := range badConfigs {
+		config, err := LoadConfig(strings.NewReader(badConfig))
+		if err == nil {
+			_, err = NewNeuralNetworkWithConfig(config)
+		}
+		if err == nil {
+			t.Fatalf("bad configuration %d should fail", i)
+		}
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	myNetwork, err := New(
+		WithLayers(3, 4, 2),
+		WithLabels("1", "2"),
+		WithPopulationSize(6),
+		WithActivation(ReLU, Softmax),
+		WithSeed(7),
+		WithLoss(CategoricalCrossEntropy{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.numberOfTrainingNetworks != 6 || myNetwork.task != classification {
+		t.Fatal("the network doesn't have the given settings")
+	}
+	if fmt.Sprint(myNetwork.network.GetActivations()) != "[relu softmax]" {
+		t.Fatal("the network doesn't have the given activations")
+	}
+	if _, ok := myNetwork.loss.(CategoricalCrossEntropy); !ok {
+		t.Fatal("the network doesn't have the given loss")
+	}
+
+	defaultNetwork, err := New(WithLayers(3, 2), WithLabels("1", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultNetwork.numberOfTrainingNetworks != defaultPopulationSize {
+		t.Fatal("the default population size isn't used")
+	}
+
+	badOptions := [][]Option{
+		{},
+		{WithLayers(), WithLabels()},
+		{WithLayers(3, 0, 2), WithLabels("1", "2")},
+		{WithLayers(3, 2)},
+		{WithLayers(3, 2), WithLabels("1")},
+		{WithLayers(3, 2), WithLabels("1", "2"), WithPopulationSize(0)},
+		{WithLayers(3, 4, 2), WithLabels("1", "2"), WithActivation(ReLU)},
+		{WithLayers(3, 2), WithLabels("1", "2"), WithActivation(Activation(-1))},
+		{WithLayers(3, 2), WithLabels("1", "2"), WithLoss(nil)},
+		{WithLayers(3, 2), WithLabels("1", "2"), WithLoss(CategoricalCrossEntropy{})},
+		{WithLayers(3, 2), WithLabels("1", "2"), WithTrainer(training.Trainer{})},
+		{withTask(regression), WithLayers(3, 2), WithLabels("1", "2")},
+	}
+	for i, opts := range badOptions {
+		if _, err := New(opts...); err == nil {
+			t.Fatalf("bad options %d should fail", i)
+		}
+	}
+}
+
+func TestNewWithTrainer(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+
+	resumedNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithTrainer(myNetwork.trainer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumedNetwork.trainer.GetGeneration() != 3 {
+		t.Fatal("the given trainer isn't used")
+	}
+	// the network takes the population size and the evolution's operators from the trainer
+	if resumedNetwork.numberOfTrainingNetworks != 5 || resumedNetwork.SetEnsembleSize(6) == nil {
+		t.Fatal("the trainer's population size isn't used: ", resumedNetwork.numberOfTrainingNetworks)
+	}
+	if resumedNetwork.selector != myNetwork.trainer.GetSelector() || resumedNetwork.mutator != myNetwork.trainer.GetMutator() {
+		t.Fatal("the trainer's selector or mutator isn't used")
+	}
+	if _, err := New(WithLayers(3, 5, 2), WithLabels("1", "2"), WithTrainer(myNetwork.trainer)); err == nil {
+		t.Fatal("trainer of a different network got through")
+	}
+}
+
+func TestReproducibleNetwork(t *testing.T) {
+	inputs := [][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}, {0.3, 0.9, 0.1}}
+	outputs := []string{"1", "2", "2"}
+	trainWithSeed := func(seed int64) []float64 {
+		myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(8), WithSeed(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := myNetwork.LoadTrainingData(inputs, outputs); err != nil {
+			t.Fatal(err)
+		}
+		if err := myNetwork.Train(10); err != nil {
+			t.Fatal(err)
+		}
+		if err := myNetwork.TrainWithAlgorithm(3, BackPropagationTraining); err != nil {
+			t.Fatal(err)
+		}
+		return myNetwork.network.GetRawParameters()
+	}
+
+	first, second, other := trainWithSeed(9), trainWithSeed(9), trainWithSeed(10)
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatal("networks with the same seed were trained differently")
+	}
+	if fmt.Sprint(first) == fmt.Sprint(other) {
+		t.Fatal("networks with different seeds were trained the same way")
+	}
+}
+
+func TestInitializers(t *testing.T) {
+	initializers := []Initializer{
+		Uniform{Limit: 0.5}, Normal{StdDev: 0.1}, XavierUniform{}, XavierNormal{}, HeUniform{}, HeNormal{},
+		LeCunUniform{}, LeCunNormal{}, Orthogonal{}, ZeroBias{Initializer: Uniform{Limit: 1}},
+	}
+	for _, initializer := range initializers {
+		myNetwork, err := New(WithLayers(8, 6, 10), WithLabels("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			WithInitializer(initializer, initializer), WithSeed(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, randomBiases := initializer.(Uniform)
+		if _, ok := initializer.(Normal); ok {
+			randomBiases = true
+		}
+		if !randomBiases && myNetwork.network.GetNodeBias(1, 0) != 0 {
+			t.Fatalf("%T should give zero biases", initializer)
+		}
+		if myNetwork.network.GetNodeWeight(0, 0, 0) == 0 {
+			t.Fatalf("%T should give random weights", initializer)
+		}
+		if uniform, ok := initializer.(Uniform); ok {
+			for _, parameter := range myNetwork.network.GetRawParameters() {
+				if math.Abs(parameter) > uniform.Limit {
+					t.Fatal("uniform initializer exceeded its limit")
+				}
+			}
+		}
+	}
+
+	// rows of the 6x8 matrix and columns of the 10x6 one have to be orthonormal
+	myNetwork, err := New(WithLayers(8, 6, 10), WithLabels("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		WithInitializer(Orthogonal{}, Orthogonal{}), WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for first := 0; first < 6; first++ {
+		for second := 0; second < 6; second++ {
+			rowsDot, columnsDot := 0.0, 0.0
+			for k := 0; k < 8; k++ {
+				rowsDot += myNetwork.network.GetNodeWeight(0, k, first) * myNetwork.network.GetNodeWeight(0, k, second)
+			}
+			for j := 0; j < 10; j++ {
+				columnsDot += myNetwork.network.GetNodeWeight(1, first, j) * myNetwork.network.GetNodeWeight(1, second, j)
+			}
+			expected := 0.0
+			if first == second {
+				expected = 1
+			}
+			if math.Abs(rowsDot-expected) > 1e-9 || math.Abs(columnsDot-expected) > 1e-9 {
+				t.Fatal("orthogonal initializer didn't give an orthogonal matrix")
+			}
+		}
+	}
+
+	badOptions := [][]Option{
+		{WithLayers(3, 4, 2), WithLabels("1", "2"), WithInitializer(HeUniform{})},
+		{WithLayers(3, 4, 2), WithLabels("1", "2"), WithInitializer(HeUniform{}, nil)},
+		{WithLayers(3, 4, 2), WithLabels("1", "2"), WithInitializer(HeUniform{}, ZeroBias{})},
+		{WithLayers(3, 4, 2), WithLabels("1", "2"), WithInitializer(ZeroBias{Initializer: ZeroBias{}}, HeUniform{})},
+	}
+	for i, opts := range badOptions {
+		if _, err := New(opts...); err == nil {
+			t.Fatalf("bad initializer options %d should fail", i)
+		}
+	}
+}
+
+func TestNodeParameters(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	numberOfParameters := myNetwork.network.GetNumberOfParameters()
+
+	// the input layer's nodes have no biases, so they are always 0 and setting them is ignored
+	myNetwork.network.SetNodeBias(0, 1, 5)
+	if bias := myNetwork.network.GetNodeBias(0, 1); bias != 0 {
+		t.Fatal("the input node has a bias: ", bias)
+	}
+	if myNetwork.network.GetNumberOfParameters() != numberOfParameters {
+		t.Fatal("the input node's bias has been stored")
+	}
+
+	myNetwork.network.SetNodeBias(1, 2, 5)
+	myNetwork.network.SetNodeWeight(0, 1, 2, 6)
+	if myNetwork.network.GetNodeBias(1, 2) != 5 || myNetwork.network.GetNodeWeight(0, 1, 2) != 6 {
+		t.Fatal("the bias or the weight hasn't been set")
+	}
+}
+
+func TestDeepNetworkInitialization(t *testing.T) {
+	layers := []int{16, 32, 32, 32, 32, 32, 32, 32, 32, 1}
+	activations := []Activation{ReLU, ReLU, ReLU, ReLU, ReLU, ReLU, ReLU, ReLU, Linear}
+	getAverageOutput := func(opts ...Option) float64 {
+		opts = append(opts, withTask(regression), WithLayers(layers...), WithActivation(activations...), WithSeed(3))
+		myNetwork, err := New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		random := rand.New(rand.NewSource(4))
+		sum := 0.0
+		for i := 0; i < 20; i++ {
+			input := make([]float64, layers[0])
+			for j := range input {
+				input[j] = random.Float64()
+			}
+			output, err := myNetwork.Predict(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum += math.Abs(output[0])
+		}
+		return sum / 20
+	}
+
+	// the default He initialization keeps values in the same range through all layers
+	if average := getAverageOutput(); average < 1e-3 || average > 10 {
+		t.Fatal("deep network with default initializers doesn't keep its values in range: ", average)
+	}
+	initializers := make([]Initializer, len(activations))
+	for i := range initializers {
+		initializers[i] = Uniform{Limit: 1}
+	}
+	if average := getAverageOutput(WithInitializer(initializers...)); average < 100 {
+		t.Fatal("deep network with uniform initializers should explode: ", average)
+	}
+}
+
+// has to be run with -race to prove that inference doesn't change the network
+func TestConcurrentInference(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 8, 2), WithLabels("1", "2"), WithPopulationSize(6), WithSeed(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}, {0.3, 0.9, 0.1}}, []string{"1", "2", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetEnsembleSize(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+
+	random := rand.New(rand.NewSource(6))
+	inputs := make([][]float64, 50)
+	expectedOutputs := make([]string, len(inputs))
+	for i := range inputs {
+		inputs[i] = []float64{random.Float64(), random.Float64(), random.Float64()}
+		outputMap, err := myNetwork.GetOutputMap(inputs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedOutputs[i] = fmt.Sprint(outputMap)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for repeat := 0; repeat < 20; repeat++ {
+				i := (worker + repeat) % len(inputs)
+				outputMap, err := myNetwork.GetOutputMap(inputs[i])
+				if err == nil {
+					_, err = myNetwork.GetNetworkResult(inputs[i])
+				}
+				if err == nil {
+					_, err = myNetwork.Predict(inputs[i])
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				if fmt.Sprint(outputMap) != expectedOutputs[i] {
+					errs <- errors.New("concurrent inference gave a different result")
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchedInference(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	inputs := make([][]float64, 1000)
+	for i := range inputs {
+		inputs[i] = []float64{random.Float64(), random.Float64(), random.Float64()}
+	}
+
+	for _, ensembleSize := range []int{1, 3} {
+		myNetwork, err := New(WithLayers(3, 8, 2), WithLabels("1", "2"), WithPopulationSize(6), WithSeed(8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}, {0.3, 0.9, 0.1}}, []string{"1", "2", "2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := myNetwork.SetEnsembleSize(ensembleSize); err != nil {
+			t.Fatal(err)
+		}
+		if err := myNetwork.Train(3); err != nil {
+			t.Fatal(err)
+		}
+
+		outputs, err := myNetwork.PredictBatch(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		labels, err := myNetwork.ClassifyBatch(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(outputs) != len(inputs) || len(labels) != len(inputs) {
+			t.Fatal("batch results have wrong length")
+		}
+		for i, input := range inputs {
+			expectedOutputs, err := myNetwork.Predict(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedLabel, err := myNetwork.GetNetworkResult(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(outputs[i]) != fmt.Sprint(expectedOutputs) || labels[i] != expectedLabel {
+				t.Fatal("batch results are different than the single ones or in a wrong order")
+			}
+		}
+	}
+
+	myNetwork, err := New(WithLayers(3, 2), WithLabels("1", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	badBatches := [][][]float64{
+		{{0.1, 0.2, 0.3}, {0.1, 0.2}},
+		{{0.1, 0.2, 0.3}, {0.1, 0.2, 1.5}},
+	}
+	for i, badBatch := range badBatches {
+		if _, err := myNetwork.PredictBatch(badBatch); err == nil {
+			t.Fatalf("bad batch %d should fail", i)
+		}
+		if _, err := myNetwork.ClassifyBatch(badBatch); err == nil {
+			t.Fatalf("bad batch %d should fail", i)
+		}
+	}
+	regressionNetwork, err := NewRegressionNetwork(1, []int{3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := regressionNetwork.ClassifyBatch(inputs); err == nil {
+		t.Fatal("regression network can't classify")
+	}
+	if outputs, err := regressionNetwork.PredictBatch(nil); err != nil || len(outputs) != 0 {
+		t.Fatal("empty batch should give no outputs")
+	}
+}
+
+func TestValidationAndEarlyStopping(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 5, 2), WithLabels("red", "notRed"), WithPopulationSize(4), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := rand.New(rand.NewSource(1))
+	var inputs [][]float64
+	var outputs []string
+	for i := 0; i < 20; i++ {
+		input := []float64{random.Float64(), random.Float64(), random.Float64()}
+		inputs = append(inputs, input)
+		if input[0] > input[1]+input[2] {
+			outputs = append(outputs, "red")
+		} else {
+			outputs = append(outputs, "notRed")
+		}
+	}
+	if err := myNetwork.LoadTrainingData(inputs, outputs); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fraction := range []float64{-0.1, 1} {
+		if err := myNetwork.SetValidationSplit(fraction); err == nil {
+			t.Fatal("bad validation split got through: ", fraction)
+		}
+	}
+	if err := myNetwork.SetValidationSplit(0.01); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(1); err == nil {
+		t.Fatal("validation split without validation data got through")
+	}
+	if err := myNetwork.SetValidationSplit(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(3, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	history := myNetwork.GetValidationHistory()
+	if len(history) != 3 || myNetwork.StoppedEarly() {
+		t.Fatal("validation hasn't been done after every iteration: ", history)
+	}
+	// the best network is ranked by the 5 data sets split for the validation
+	_, validationData, err := myNetwork.splitTrainingData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validationData) != 5 {
+		t.Fatal("wrong number of validation data: ", len(validationData))
+	}
+	if cost := myNetwork.network.GetAverageCost(validationData, myNetwork.loss); cost != myNetwork.GetTrainingCost() {
+		t.Fatal("the network hasn't been measured on the validation data: ", cost, myNetwork.GetTrainingCost())
+	}
+
+	if err := myNetwork.LoadRegressionValidationData([][]float64{{0, 0, 0}}, [][]float64{{0, 0}}); err == nil {
+		t.Fatal("regression validation data got into the classification network")
+	}
+	if err := myNetwork.LoadValidationData([][]float64{{0, 0, 0}}, []string{"blue"}); err == nil {
+		t.Fatal("validation data with an unknown label got through")
+	}
+	if err := myNetwork.LoadValidationData(inputs[:5], outputs[:5]); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetEarlyStopping(training.EarlyStoppingConfig{Patience: 0}); err == nil {
+		t.Fatal("bad early stopping got through")
+	}
+	if err := myNetwork.SetEarlyStopping(training.EarlyStoppingConfig{Patience: 1, MinDelta: 1e9, RestoreBest: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(10, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	if !myNetwork.StoppedEarly() || len(myNetwork.GetValidationHistory()) != 5 {
+		t.Fatal("the training hasn't been stopped early: ", len(myNetwork.GetValidationHistory()))
+	}
+	if cost := myNetwork.network.GetAverageCost(myNetwork.validationData, myNetwork.loss); cost != myNetwork.GetTrainingCost() {
+		t.Fatal("the network hasn't been measured on the loaded validation data")
+	}
+}
+
+func TestValidationSplitOfOrderedData(t *testing.T) {
+	myNetwork, err := New(WithLayers(1, 2), WithLabels("a", "b"), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inputs [][]float64
+	var outputs []string
+	for i := 0; i < 20; i++ {
+		inputs = append(inputs, []float64{float64(i) / 20})
+		if i < 10 {
+			outputs = append(outputs, "a")
+		} else {
+			outputs = append(outputs, "b")
+		}
+	}
+	if err := myNetwork.LoadTrainingData(inputs, outputs); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetValidationSplit(0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	trainingData, validationData, err := myNetwork.splitTrainingData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trainingData) != 10 || len(validationData) != 10 {
+		t.Fatal("wrong split: ", len(trainingData), len(validationData))
+	}
+	labels := map[string]bool{}
+	for _, data := range validationData {
+		labels[data.GetExpOutput()] = true
+	}
+	if len(labels) != 2 {
+		t.Fatal("the validation data has only the last labels: ", labels)
+	}
+	_, nextValidationData, _ := myNetwork.splitTrainingData()
+	if fmt.Sprint(nextValidationData) != fmt.Sprint(validationData) {
+		t.Fatal("the validation data changed between the splits")
+	}
+}
+
+func TestHeldOutValidationData(t *testing.T) {
+	var inputs [][]float64
+	var outputs []string
+	for i := 0; i < 40; i++ {
+		inputs = append(inputs, []float64{float64(i) / 40})
+		outputs = append(outputs, []string{"a", "b"}[i%2])
+	}
+	getValidationInputs := func(myNetwork *Network) map[float64]bool {
+		_, validationData, err := myNetwork.splitTrainingData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		validationInputs := map[float64]bool{}
+		for _, data := range validationData {
+			validationInputs[data.GetInputs()[0]] = true
+		}
+		return validationInputs
+	}
+
+	myNetwork, err := New(WithLayers(1, 2), WithLabels("a", "b"), WithPopulationSize(4), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetValidationSplit(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData(inputs[:20], outputs[:20]); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+	firstValidationInputs := getValidationInputs(myNetwork)
+
+	// only the new data sets are split, the held out ones stay in the validation
+	if err := myNetwork.LoadTrainingData(inputs[20:], outputs[20:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+	validationInputs := getValidationInputs(myNetwork)
+	if len(validationInputs) != 10 {
+		t.Fatal("wrong number of validation data: ", len(validationInputs))
+	}
+	for input := range firstValidationInputs {
+		if !validationInputs[input] {
+			t.Fatal("held out data set has got into the training: ", input)
+		}
+	}
+
+	// the resumed trainer holds out the same data sets
+	var buffer bytes.Buffer
+	if err := myNetwork.CheckpointTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	resumedNetwork, err := New(WithLayers(1, 2), WithLabels("a", "b"), WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedNetwork.SetValidationSplit(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedNetwork.ResumeTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumedNetwork.LoadTrainingData(inputs, outputs); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(getValidationInputs(resumedNetwork)) != fmt.Sprint(validationInputs) {
+		t.Fatal("the resumed network holds out different data sets")
+	}
+	if err := resumedNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// a network with less data than held out can't use the trainer
+	otherNetwork, err := New(WithLayers(1, 2), WithLabels("a", "b"), WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := myNetwork.CheckpointTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := otherNetwork.ResumeTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := otherNetwork.SetValidationSplit(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := otherNetwork.LoadTrainingData(inputs[:20], outputs[:20]); err != nil {
+		t.Fatal(err)
+	}
+	if err := otherNetwork.Train(1); err == nil {
+		t.Fatal("training with less data than held out got through")
+	}
+}
+
+func TestEvaluation(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 5, 2), WithLabels("red", "notRed"), WithPopulationSize(2), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := [][]float64{{1, 0, 0}, {0.9, 0.1, 0.2}, {0, 1, 0}, {0.1, 0.2, 0.9}}
+	labels := []string{"red", "red", "notRed", "notRed"}
+	if err := myNetwork.LoadTrainingData(inputs, labels); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(50, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := myNetwork.Evaluate(inputs, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := myNetwork.ClassifyBatch(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	correct := 0
+	for i, result := range results {
+		if result == labels[i] {
+			correct++
+			if report.ConfusionMatrix[labels[i]][result] == 0 {
+				t.Fatal("the confusion matrix doesn't have a correct prediction")
+			}
+		}
+	}
+	if report.Accuracy != float64(correct)/float64(len(inputs)) || report.Support != len(inputs) || report.TopK(2) != 1 {
+		t.Fatal("wrong evaluation: ", report)
+	}
+
+	if _, err := myNetwork.Evaluate(inputs, labels[:1]); err == nil {
+		t.Fatal("different number of inputs and labels got through")
+	}
+	if _, err := myNetwork.Evaluate([][]float64{{2, 0, 0}}, []string{"red"}); err == nil {
+		t.Fatal("input out of range got through")
+	}
+	if _, err := myNetwork.Evaluate(nil, nil); err == nil {
+		t.Fatal("no data got through")
+	}
+	regressionNetwork, err := NewRegressionNetwork(1, []int{3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := regressionNetwork.Evaluate(inputs[:1], []string{""}); err == nil {
+		t.Fatal("regression network has been evaluated")
+	}
+}
+
+func TestTrainingEvents(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(4), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	generations := 0
+	myNetwork.AddCallback(training.CallbackFuncs{GenerationEnd: func(event training.TrainingEvent) error {
+		generations++
+		return nil
+	}})
+	events := make(chan training.TrainingEvent, 100)
+	myNetwork.SetEventChannel(events)
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if generations != 3 {
+		t.Fatal("the callback hasn't been called after every generation: ", generations)
+	}
+	// every generation uses all data, so it is an epoch
+	if len(events) != 3*3+1 {
+		t.Fatal("wrong number of events: ", len(events))
+	}
+
+	// callbacks added after the trainer was created are used too and can stop the training
+	stop := true
+	myNetwork.AddCallback(training.CallbackFuncs{GenerationStart: func(event training.TrainingEvent) error {
+		if stop && event.Generation == 5 {
+			return training.ErrStopTraining
+		}
+		return nil
+	}})
+	myNetwork.SetEventChannel(nil)
+	if err := myNetwork.TrainWithAlgorithm(10, BackPropagationTraining); err != nil {
+		t.Fatal(err)
+	}
+	if !myNetwork.StoppedEarly() || generations != 5 {
+		t.Fatal("the callback hasn't stopped the training: ", generations)
+	}
+
+	// the callbacks are kept after resuming the trainer
+	stop = false
+	var buffer bytes.Buffer
+	if err := myNetwork.CheckpointTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.ResumeTrainer(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(1); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.StoppedEarly() || generations != 6 {
+		t.Fatal("the callbacks haven't been kept after resuming the trainer: ", generations)
+	}
+}
+
+func TestTrainContext(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(4), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	originalParameters := fmt.Sprint(myNetwork.network.GetRawParameters())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	myNetwork.AddCallback(training.CallbackFuncs{GenerationEnd: func(event training.TrainingEvent) error {
+		if event.Generation == 2 {
+			cancel()
+		}
+		return nil
+	}})
+	if err := myNetwork.TrainWithAlgorithmContext(ctx, 100, BackPropagationTraining); !errors.Is(err, context.Canceled) {
+		t.Fatal("the context's error hasn't been returned: ", err)
+	}
+	if myNetwork.trainer.GetGeneration() != 2 {
+		t.Fatal("the training hasn't been stopped: ", myNetwork.trainer.GetGeneration())
+	}
+	if fmt.Sprint(myNetwork.network.GetRawParameters()) == originalParameters {
+		t.Fatal("the network hasn't been replaced by the best one found so far")
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if err := myNetwork.TrainContext(ctx, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("the deadline hasn't stopped the training: ", err)
+	}
+	if err := myNetwork.TrainContext(context.Background(), 0); err == nil || errors.Is(err, context.Canceled) {
+		t.Fatal("wrong number of iterations got through")
+	}
+}
+
+func TestSettingSelector(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(6), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetSelector(training.TournamentSelection{Size: 0}); err == nil {
+		t.Fatal("bad selector got through")
+	}
+	if err := myNetwork.SetSelector(training.RankSelection{Pressure: 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := myNetwork.trainer.GetSelector().(training.RankSelection); !ok {
+		t.Fatal("the trainer didn't get the selector")
+	}
+	if err := myNetwork.SetSelector(training.StochasticUniversalSampling{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := myNetwork.trainer.GetSelector().(training.StochasticUniversalSampling); !ok {
+		t.Fatal("the selector of the trained network hasn't been replaced")
+	}
+}
+
+func TestSettingCrossover(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(6), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetCrossover(training.UniformCrossover{Bias: -1}); err == nil {
+		t.Fatal("bad crossover got through")
+	}
+	if err := myNetwork.SetCrossover(training.LayerCrossover{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.trainer.GetCrossover() != (training.LayerCrossover{}) {
+		t.Fatal("the trainer didn't get the crossover")
+	}
+	if err := myNetwork.SetCrossover(training.TwoPointCrossover{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.Train(3); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.trainer.GetCrossover() != (training.TwoPointCrossover{}) {
+		t.Fatal("the crossover of the trained network hasn't been replaced")
+	}
+}
+
+func TestSettingMutator(t *testing.T) {
+	myNetwork, err := New(WithLayers(3, 4, 2), WithLabels("1", "2"), WithPopulationSize(6), WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.LoadTrainingData([][]float64{{1, 0.5, 0.6}, {0, 0.2, 0.1}}, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.SetMutator(training.ResetMutation{Range: 0, Probability: 0.5}); err == nil {
+		t.Fatal("bad mutator got through")
+	}
+	if err := myNetwork.SetMutator(training.GaussianMutation{Sigma: 0.2, Probability: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(3, EvolutionTraining); err != nil {
+		t.Fatal(err)
+	}
+	if myNetwork.trainer.GetMutator() != (training.GaussianMutation{Sigma: 0.2, Probability: 0.5}) {
+		t.Fatal("the trainer didn't get the mutator")
+	}
+	history := myNetwork.GetMutationHistory()
+	if len(history) != 3 || history[0].MutatedFraction == 0 || history[0].MaxChange == 0 {
+		t.Fatal("wrong mutation history: ", history)
+	}
+	if err := myNetwork.SetMutator(training.SelfAdaptiveMutation{InitialSigma: 0.5, Probability: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := myNetwork.TrainWithAlgorithm(3, EvolutionTraining); err != nil {
+		t.Fatal(err)
+	}
+	if history := myNetwork.GetMutationHistory(); len(history) != 6 || history[5].AverageStrength == 0 {
+		t.Fatal("the mutator of the trained network hasn't been replaced: ", history)
+	}
+}
@@ -1,78 +1,276 @@
 package NeuralNetwork
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
-	"time"
 
 	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
 	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
 )
 
-// for random generation of mutations and random initialization
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
-type neuralNetwork struct {
+// Network is a neural network together with its training data and everything needed to train it.
+// Inference methods don't change it, so they can be called by many goroutines at once,
+// but not at the same time as training or loading data
+type Network struct {
 	network                  network.Network
 	trainer                  training.Trainer
 	numberOfTrainingNetworks int
 	trainingData             network.DataSets
+	optimizer                training.Optimizer
+	loss                     network.Loss
+	task                     task
+	ensembleSize             int
+	ensemble                 []network.Network // the best networks which outputs are averaged, if there are more than one
+	algorithm                training.Algorithm
+	evolution                *training.EvolutionConfig // if nil the trainer's default one is used
+	selector                 training.Selector         // if nil the evolution config decides how parents are chosen
+	crossover                training.Crossover        // if nil the trainer's default one is used
+	mutator                  training.Mutator          // if nil the evolution config decides how children are mutated
+	batch                    *training.BatchConfig     // if nil the trainer's default one is used
+	validationData           network.DataSets
+	validationSplit          float64                       // the fraction of training data used for validation if there is no validation data
+	heldOut                  []bool                        // which training data sets are held out by the validation split, new ones are added to it
+	splitTraining            network.DataSets              // training data sets which aren't held out, built again when the held out ones change
+	splitValidation          network.DataSets              // the held out training data sets
+	earlyStopping            *training.EarlyStoppingConfig // if nil the training isn't stopped early
+	callbacks                []training.Callback
+	events                   chan<- training.TrainingEvent
+	random                   *rand.Rand // the source of all random numbers used by the network and its trainer
+}
+
+// determines what the network's outputs mean
+type task int
+
+const (
+	// every output node has a label and the best one is the result
+	classification task = iota
+	// output nodes' values are the result
+	regression
+	// every output node has a label and all labels above a threshold are the result
+	multiLabel
+)
+
+var taskNames = map[task]string{
+	classification: "classification",
+	regression:     "regression",
+	multiLabel:     "multi-label classification",
+}
+
+func (myTask task) String() string {
+	return taskNames[myTask]
+}
+
+// returns the task with the given name
+func parseTask(name string) (task, error) {
+	for myTask, taskName := range taskNames {
+		if taskName == name {
+			return myTask, nil
+		}
+	}
+	return 0, errors.New("unknown task: " + name)
 }
 
+// Activation functions which can be used by the network's layers
+type Activation = network.Activation
+
+const (
+	Sigmoid   = network.Sigmoid
+	Tanh      = network.Tanh
+	ReLU      = network.ReLU
+	LeakyReLU = network.LeakyReLU
+	ELU       = network.ELU
+	GELU      = network.GELU
+	Softplus  = network.Softplus
+	Linear    = network.Linear
+	Softmax   = network.Softmax
+)
+
+// Initializers which give layers their initial weights and biases
+type (
+	Initializer   = network.Initializer
+	Uniform       = network.Uniform
+	Normal        = network.Normal
+	XavierUniform = network.XavierUniform
+	XavierNormal  = network.XavierNormal
+	HeUniform     = network.HeUniform
+	HeNormal      = network.HeNormal
+	LeCunUniform  = network.LeCunUniform
+	LeCunNormal   = network.LeCunNormal
+	Orthogonal    = network.Orthogonal
+	ZeroBias      = network.ZeroBias
+	// describes an initializer in the configuration
+	InitializerConfig = network.InitializerConfig
+)
+
+// Loss functions which can be used to measure the network's cost
+type (
+	Loss                    = network.Loss
+	MeanSquaredError        = network.MeanSquaredError
+	BinaryCrossEntropy      = network.BinaryCrossEntropy
+	CategoricalCrossEntropy = network.CategoricalCrossEntropy
+	Hinge                   = network.Hinge
+	Huber                   = network.Huber
+)
+
 // Retruns an initialized neural network ready to be given data and to be trained.
 // Number of training networks has to be bigger than 0.
 // Amount of layers and nodes has to bigger than 0.
-// Amount of output labels has to be equal to number of output nodes
-func NewNeuralNetwork(numberOfTrainingNetworks int, nodesPerLayer []int, outputLabels []string) (*neuralNetwork, error) {
-	if err := validateNetworkInit(numberOfTrainingNetworks, nodesPerLayer, outputLabels); err != nil {
-		return nil, err
-	}
+// Amount of output labels has to be equal to number of output nodes.
+// Activations are optional, if given there has to be one for every layer except the input one.
+// Otherwise every layer uses sigmoid
+func NewNeuralNetwork(numberOfTrainingNetworks int, nodesPerLayer []int, outputLabels []string, activations ...Activation) (*Network, error) {
+	return New(WithPopulationSize(numberOfTrainingNetworks), WithLayers(nodesPerLayer...), WithLabels(outputLabels...), WithActivation(activations...))
+}
+
+// Retruns an initialized neural network for which inputs can belong to many labels at once.
+// Number of training networks has to be bigger than 0.
+// Amount of layers and nodes has to bigger than 0.
+// Amount of output labels has to be equal to number of output nodes.
+// Activations are optional, if given there has to be one for every layer except the input one.
+// Otherwise every layer uses sigmoid. The binary cross entropy is used as the loss
+func NewMultiLabelNetwork(numberOfTrainingNetworks int, nodesPerLayer []int, outputLabels []string, activations ...Activation) (*Network, error) {
+	return New(withTask(multiLabel), WithPopulationSize(numberOfTrainingNetworks), WithLayers(nodesPerLayer...), WithLabels(outputLabels...), WithActivation(activations...))
+}
 
-	// initialize networks
-	var neuralNet neuralNetwork
-	neuralNet.network.InitializeNetwork(nodesPerLayer, outputLabels)
-	return &neuralNet, nil
+// Retruns an initialized neural network which outputs numbers instead of labels.
+// Number of training networks has to be bigger than 0.
+// Amount of layers and nodes has to bigger than 0.
+// Activations are optional, if given there has to be one for every layer except the input one.
+// Otherwise hidden layers use sigmoid and the output layer is linear
+func NewRegressionNetwork(numberOfTrainingNetworks int, nodesPerLayer []int, activations ...Activation) (*Network, error) {
+	return New(withTask(regression), WithPopulationSize(numberOfTrainingNetworks), WithLayers(nodesPerLayer...), WithActivation(activations...))
 }
 
-func (neuralNet *neuralNetwork) PrintNetworkSchema() {
+func (neuralNet *Network) PrintNetworkSchema() {
 	nodesPerLayer := neuralNet.network.GetNetworkStructure()
+	activations := neuralNet.network.GetActivations()
 
 	fmt.Println("<========================>")
 	fmt.Println(" A neural network schema:")
 	for i, nodes := range nodesPerLayer {
-		fmt.Printf(" Layer %d: %d nodes\n", i, nodes)
+		if i == 0 {
+			fmt.Printf(" Layer %d: %d nodes\n", i, nodes)
+		} else {
+			fmt.Printf(" Layer %d: %d nodes, %s\n", i, nodes, activations[i-1])
+		}
 	}
 	fmt.Println("<========================>")
 }
 
 // Returns the best a map where output label are keys and outputs are values for given input data.
 // Inputs have to be between 0 and 1
-func (neuralNet *neuralNetwork) GetOutputMap(inputData []float64) (map[string]float64, error) {
+func (neuralNet *Network) GetOutputMap(inputData []float64) (map[string]float64, error) {
+	if err := neuralNet.validateTask(classification, multiLabel); err != nil {
+		return nil, err
+	}
 	err := neuralNet.validateInputData(inputData)
 	if err != nil {
 		return nil, err
 	}
 
-	return neuralNet.network.GetOutputsMap(inputData), nil
+	outputMap := make(map[string]float64)
+	for i, output := range neuralNet.getOutputs(inputData) {
+		outputMap[neuralNet.network.GetOutputLabels()[i]] = output
+	}
+	return outputMap, nil
 }
 
 // Returns the best output label for given input data. Inputs have to be between 0 and 1
-func (neuralNet *neuralNetwork) GetNetworkResult(inputData []float64) (string, error) {
+func (neuralNet *Network) GetNetworkResult(inputData []float64) (string, error) {
+	if err := neuralNet.validateTask(classification, multiLabel); err != nil {
+		return "", err
+	}
 	err := neuralNet.validateInputData(inputData)
 	if err != nil {
 		return "", err
 	}
 
-	label, _ := neuralNet.network.GetBestOutput(inputData)
-	return label, nil
+	outputs := neuralNet.getOutputs(inputData)
+	bestIndex := 0
+	for i, output := range outputs {
+		if output > outputs[bestIndex] {
+			bestIndex = i
+		}
+	}
+	return neuralNet.network.GetOutputLabels()[bestIndex], nil
+}
+
+// Returns labels of all output nodes which values are bigger than the threshold for given input data.
+// Labels are ordered the same way as output nodes. Inputs have to be between 0 and 1
+func (neuralNet *Network) GetLabelsAboveThreshold(inputData []float64, threshold float64) ([]string, error) {
+	if err := neuralNet.validateTask(classification, multiLabel); err != nil {
+		return nil, err
+	}
+	err := neuralNet.validateInputData(inputData)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []string{}
+	outputLabels := neuralNet.network.GetOutputLabels()
+	for i, output := range neuralNet.getOutputs(inputData) {
+		if output > threshold {
+			labels = append(labels, outputLabels[i])
+		}
+	}
+	return labels, nil
+}
+
+// Returns values of all output nodes for given input data. Inputs have to be between 0 and 1
+func (neuralNet *Network) Predict(inputData []float64) ([]float64, error) {
+	err := neuralNet.validateInputData(inputData)
+	if err != nil {
+		return nil, err
+	}
+
+	return neuralNet.getOutputs(inputData), nil
+}
+
+// returns outputs of the network or the averaged outputs of the ensemble
+func (neuralNet *Network) getOutputs(inputData []float64) []float64 {
+	if len(neuralNet.ensemble) <= 1 {
+		return neuralNet.network.GetOutputs(inputData)
+	}
+
+	outputs := make([]float64, neuralNet.NumberOfOutputNodes())
+	for i := range neuralNet.ensemble {
+		for j, output := range neuralNet.ensemble[i].GetOutputs(inputData) {
+			outputs[j] += output / float64(len(neuralNet.ensemble))
+		}
+	}
+	return outputs
+}
+
+// Algorithms which can be used to train the network
+const (
+	EvolutionTraining       = training.Evolution
+	BackPropagationTraining = training.BackPropagation
+)
+
+// Trains the network iterations times using the evolution algorithm or the one given in the configuration.
+// The training data has to be loaded first
+func (neuralNet *Network) Train(iterations int) error {
+	return neuralNet.TrainWithAlgorithmContext(context.Background(), iterations, neuralNet.algorithm)
 }
 
-// Trains the network iterations times.
+// Trains the network iterations times using the given algorithm.
 // The training data has to be loaded first
-func (neuralNet *neuralNetwork) Train(iterations int) error {
+func (neuralNet *Network) TrainWithAlgorithm(iterations int, algorithm training.Algorithm) error {
+	return neuralNet.TrainWithAlgorithmContext(context.Background(), iterations, algorithm)
+}
+
+// Trains like Train, but stops between generations or during the cost evaluation when the context is done.
+// Then the network becomes the best one found so far and the context's error is returned
+func (neuralNet *Network) TrainContext(ctx context.Context, iterations int) error {
+	return neuralNet.TrainWithAlgorithmContext(ctx, iterations, neuralNet.algorithm)
+}
+
+// Trains like TrainWithAlgorithm, but stops between generations or during the cost evaluation when the context is done.
+// Then the network becomes the best one found so far and the context's error is returned
+func (neuralNet *Network) TrainWithAlgorithmContext(ctx context.Context, iterations int, algorithm training.Algorithm) error {
 	if iterations <= 0 {
 		return errors.New("number of iterations has to be bigger than one")
 	} else if len(neuralNet.trainingData) == 0 {
@@ -80,27 +278,242 @@ func (neuralNet *neuralNetwork) Train(iterations int) error {
 	}
 
 	if !neuralNet.trainer.Initialized {
-		neuralNet.trainer = training.NewTrainer(neuralNet.network, neuralNet.numberOfTrainingNetworks)
+		neuralNet.trainer = training.NewTrainer(neuralNet.network, neuralNet.numberOfTrainingNetworks, neuralNet.random.Int63())
+		if neuralNet.optimizer != nil {
+			neuralNet.trainer.SetOptimizer(neuralNet.optimizer)
+		}
+		neuralNet.trainer.SetLoss(neuralNet.loss)
+		if neuralNet.evolution != nil {
+			if err := neuralNet.trainer.SetEvolutionConfig(*neuralNet.evolution); err != nil {
+				return err
+			}
+		}
+		if err := neuralNet.trainer.SetSelector(neuralNet.selector); err != nil {
+			return err
+		}
+		if err := neuralNet.trainer.SetCrossover(neuralNet.crossover); err != nil {
+			return err
+		}
+		if err := neuralNet.trainer.SetMutator(neuralNet.mutator); err != nil {
+			return err
+		}
+		if neuralNet.batch != nil {
+			if err := neuralNet.trainer.SetBatchConfig(*neuralNet.batch); err != nil {
+				return err
+			}
+		}
+		if neuralNet.earlyStopping != nil {
+			if err := neuralNet.trainer.SetEarlyStopping(*neuralNet.earlyStopping); err != nil {
+				return err
+			}
+		}
+		neuralNet.setListeners(&neuralNet.trainer)
+	}
+
+	trainingData, validationData, err := neuralNet.splitTrainingData()
+	if err != nil {
+		return err
+	}
+	neuralNet.trainer.SetHeldOutData(neuralNet.heldOut)
+	neuralNet.trainer.SetValidationData(validationData)
+	err = neuralNet.trainer.TrainContext(ctx, trainingData, iterations, algorithm)
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	// the best trained networks replace the current one
+	neuralNet.ensemble = neuralNet.trainer.BestNetworks(neuralNet.ensembleSize)
+	neuralNet.network = neuralNet.ensemble[0]
+	return err
+}
+
+// Returns the cost of the best network measured at the end of the last training.
+// It is measured on the validation data if there is any
+func (neuralNet *Network) GetTrainingCost() float64 {
+	return neuralNet.network.GetCost()
+}
+
+// Sets how many best networks are used after the training. If there are more than one
+// their outputs are averaged. It can't be bigger than the number of training networks.
+// Only the best network is saved by Save and SaveBinary
+func (neuralNet *Network) SetEnsembleSize(size int) error {
+	if size <= 0 || size > neuralNet.numberOfTrainingNetworks {
+		return errors.New("ensemble size has to be between 1 and the number of training networks")
+	}
+	neuralNet.ensembleSize = size
+	return nil
+}
+
+// Sets the optimizer used by the back propagation training e.g. training.NewAdam(0.001, 0.9, 0.999).
+// Its state is kept between the Train calls until it is replaced
+func (neuralNet *Network) SetOptimizer(optimizer training.Optimizer) error {
+	if optimizer == nil {
+		return errors.New("the optimizer can't be nil")
+	}
+
+	neuralNet.optimizer = optimizer
+	if neuralNet.trainer.Initialized {
+		neuralNet.trainer.SetOptimizer(optimizer)
+	}
+	return nil
+}
+
+// Sets the loss used to measure the network's cost by both evolution and back propagation.
+// The categorical cross entropy can only be used with the softmax output layer
+func (neuralNet *Network) SetLoss(loss Loss) error {
+	if err := neuralNet.validateLoss(loss); err != nil {
+		return err
+	}
+
+	neuralNet.loss = loss
+	if neuralNet.trainer.Initialized {
+		neuralNet.trainer.SetLoss(loss)
+	}
+	return nil
+}
+
+// Sets the hyperparameters of the evolution algorithm
+func (neuralNet *Network) SetEvolutionConfig(config training.EvolutionConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	neuralNet.evolution = &config
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetEvolutionConfig(config)
+	}
+	return nil
+}
+
+// Sets how the evolution chooses parents e.g. training.TournamentSelection{Size: 3}.
+// Nil brings back the choice described by the evolution config
+func (neuralNet *Network) SetSelector(selector training.Selector) error {
+	if err := training.ValidateSelector(selector); err != nil {
+		return err
+	}
+
+	neuralNet.selector = selector
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetSelector(selector)
+	}
+	return nil
+}
+
+// Sets how the evolution mixes parents' genes e.g. training.BlendCrossover{Alpha: 0.5}.
+// Nil brings back the uniform crossover taking genes from both parents with the same chance
+func (neuralNet *Network) SetCrossover(crossover training.Crossover) error {
+	if err := training.ValidateCrossover(crossover); err != nil {
+		return err
+	}
+
+	neuralNet.crossover = crossover
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetCrossover(crossover)
+	}
+	return nil
+}
+
+// Sets how the evolution mutates children e.g. training.GaussianMutation{Sigma: 0.1, Probability: 0.2}.
+// Nil brings back the uniform mutation of every weight and bias within ±StrengthOfEvolution
+func (neuralNet *Network) SetMutator(mutator training.Mutator) error {
+	if err := training.ValidateMutator(mutator); err != nil {
+		return err
+	}
+
+	neuralNet.mutator = mutator
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetMutator(mutator)
+	}
+	return nil
+}
+
+// Returns how much the children of every generation trained with the evolution were mutated
+func (neuralNet *Network) GetMutationHistory() []training.MutationStatistics {
+	return neuralNet.trainer.GetMutationHistory()
+}
+
+// Sets how the training data is split into batches. By default the evolution measures the costs
+// on all training data and the back propagation updates the network after every data set
+func (neuralNet *Network) SetBatchConfig(config training.BatchConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	neuralNet.batch = &config
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetBatchConfig(config)
+	}
+	return nil
+}
+
+// Writes the trainer's whole state so the training can be continued later with ResumeTrainer.
+// The network has to be trained at least once first
+func (neuralNet *Network) CheckpointTrainer(w io.Writer) error {
+	if !neuralNet.trainer.Initialized {
+		return errors.New("the network hasn't been trained yet")
 	}
+	return neuralNet.trainer.Checkpoint(w)
+}
+
+// Replaces the trainer with the one written by CheckpointTrainer.
+// Next Train calls continue its training. The saved networks have to have the same structure as this one
+func (neuralNet *Network) ResumeTrainer(r io.Reader) error {
+	trainer, err := training.ResumeTrainer(r)
+	if err != nil {
+		return err
+	}
+	return neuralNet.useTrainer(trainer)
+}
 
-	neuralNet.trainer.Train(neuralNet.trainingData, iterations)
+// replaces the trainer with the given one after checking if it can train this network
+func (neuralNet *Network) useTrainer(trainer training.Trainer) error {
+	if !trainer.HasSameArchitecture(&neuralNet.network) {
+		return errors.New("the saved trainer's networks have different structure, output labels or activations")
+	}
+	if err := neuralNet.validateLoss(trainer.GetLoss()); err != nil {
+		return err
+	}
+
+	neuralNet.setListeners(&trainer)
+	neuralNet.trainer = trainer
+	neuralNet.numberOfTrainingNetworks = trainer.GetNumberOfNetworks()
+	neuralNet.optimizer = trainer.GetOptimizer()
+	neuralNet.loss = trainer.GetLoss()
+	evolution := trainer.GetEvolutionConfig()
+	neuralNet.evolution = &evolution
+	batch := trainer.GetBatchConfig()
+	neuralNet.batch = &batch
+	neuralNet.selector = trainer.GetSelector()
+	neuralNet.crossover = trainer.GetCrossover()
+	neuralNet.mutator = trainer.GetMutator()
+	neuralNet.earlyStopping = nil
+	if earlyStopping, ok := trainer.GetEarlyStopping(); ok {
+		neuralNet.earlyStopping = &earlyStopping
+	}
+	// the data sets held out before are held out again, so they don't get into the training
+	if heldOut := trainer.GetHeldOutData(); heldOut != nil {
+		neuralNet.setHeldOutData(heldOut)
+	}
 	return nil
 }
 
 // Returns the amount of network's input nodes
-func (neuralNet *neuralNetwork) NumberOfInputNodes() int {
+func (neuralNet *Network) NumberOfInputNodes() int {
 	nodesPerLayer := neuralNet.network.GetNetworkStructure()
 	return nodesPerLayer[0]
 }
 
 // Returns the amount of network's output nodes
-func (neuralNet *neuralNetwork) NumberOfOutputNodes() int {
+func (neuralNet *Network) NumberOfOutputNodes() int {
 	nodesPerLayer := neuralNet.network.GetNetworkStructure()
 	return nodesPerLayer[len(nodesPerLayer)-1]
 }
 
 // Assings the given data to the trainer replacing the old data
-func (neuralNet *neuralNetwork) LoadTrainingData(inputs [][]float64, outputs []string) error {
+func (neuralNet *Network) LoadTrainingData(inputs [][]float64, outputs []string) error {
+	if err := neuralNet.validateTask(classification); err != nil {
+		return err
+	}
 	if err := neuralNet.validateTrainingInputData(inputs, outputs); err != nil {
 		return err
 	}
@@ -114,7 +527,10 @@ func (neuralNet *neuralNetwork) LoadTrainingData(inputs [][]float64, outputs []s
 }
 
 // Appends given given data set to training data sets
-func (neuralNet *neuralNetwork) AddSingleTrainingData(input []float64, output string) error {
+func (neuralNet *Network) AddSingleTrainingData(input []float64, output string) error {
+	if err := neuralNet.validateTask(classification); err != nil {
+		return err
+	}
 	if err := neuralNet.validateTrainingInputData([][]float64{input}, []string{output}); err != nil {
 		return err
 	}
@@ -124,3 +540,69 @@ func (neuralNet *neuralNetwork) AddSingleTrainingData(input []float64, output st
 	neuralNet.trainingData = append(neuralNet.trainingData, newData)
 	return nil
 }
+
+// Assigns the given regression data to the trainer replacing the old data.
+// Every target has to have a value for each output node
+func (neuralNet *Network) LoadRegressionTrainingData(inputs [][]float64, targets [][]float64) error {
+	if err := neuralNet.validateTask(regression); err != nil {
+		return err
+	}
+	if err := neuralNet.validateRegressionInputData(inputs, targets); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(inputs); i++ {
+		var data network.Data
+		data.SetRegressionData(inputs[i], targets[i])
+		neuralNet.trainingData = append(neuralNet.trainingData, data)
+	}
+	return nil
+}
+
+// Appends given regression data set to training data sets
+func (neuralNet *Network) AddSingleRegressionTrainingData(input []float64, target []float64) error {
+	if err := neuralNet.validateTask(regression); err != nil {
+		return err
+	}
+	if err := neuralNet.validateRegressionInputData([][]float64{input}, [][]float64{target}); err != nil {
+		return err
+	}
+
+	var newData network.Data
+	newData.SetRegressionData(input, target)
+	neuralNet.trainingData = append(neuralNet.trainingData, newData)
+	return nil
+}
+
+// Assigns the given multi-label data to the trainer replacing the old data.
+// Every input can have any number of expected outputs
+func (neuralNet *Network) LoadMultiLabelTrainingData(inputs [][]float64, outputs [][]string) error {
+	if err := neuralNet.validateTask(multiLabel); err != nil {
+		return err
+	}
+	if err := neuralNet.validateMultiLabelInputData(inputs, outputs); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(inputs); i++ {
+		var data network.Data
+		data.SetMultiLabelData(inputs[i], outputs[i])
+		neuralNet.trainingData = append(neuralNet.trainingData, data)
+	}
+	return nil
+}
+
+// Appends given multi-label data set to training data sets
+func (neuralNet *Network) AddSingleMultiLabelTrainingData(input []float64, outputs []string) error {
+	if err := neuralNet.validateTask(multiLabel); err != nil {
+		return err
+	}
+	if err := neuralNet.validateMultiLabelInputData([][]float64{input}, [][]string{outputs}); err != nil {
+		return err
+	}
+
+	var newData network.Data
+	newData.SetMultiLabelData(input, outputs)
+	neuralNet.trainingData = append(neuralNet.trainingData, newData)
+	return nil
+}
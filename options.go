@@ -0,0 +1,189 @@
+package NeuralNetwork
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
+)
+
+// the number of training networks used if it isn't given
+const defaultPopulationSize = 10
+
+// Option sets one of the network's settings given to New
+type Option func(*settings) error
+
+// all settings gathered from the options before the network is created
+type settings struct {
+	task                     task
+	nodesPerLayer            []int
+	outputLabels             []string
+	activations              []Activation
+	initializers             []Initializer
+	numberOfTrainingNetworks int
+	seed                     int64
+	trainer                  *training.Trainer
+	loss                     Loss
+}
+
+// Returns an initialized neural network ready to be given data and to be trained.
+// All options are validated before the network is created. Layers are required,
+// labels are required by all tasks except regression and the population size is 10 by default
+func New(opts ...Option) (*Network, error) {
+	mySettings := settings{
+		task:                     classification,
+		numberOfTrainingNetworks: defaultPopulationSize,
+		seed:                     time.Now().UnixNano(),
+	}
+	for _, opt := range opts {
+		if err := opt(&mySettings); err != nil {
+			return nil, err
+		}
+	}
+	if err := mySettings.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(mySettings.initializers) == 0 {
+		mySettings.initializers = nil
+	}
+	activations := mySettings.activations
+	if len(activations) == 0 {
+		activations = nil
+		if mySettings.task == regression && len(mySettings.nodesPerLayer) > 1 {
+			// hidden layers use sigmoid and the output layer is linear
+			activations = make([]Activation, len(mySettings.nodesPerLayer)-1)
+			for i := range activations {
+				activations[i] = Sigmoid
+			}
+			activations[len(activations)-1] = Linear
+		}
+	}
+
+	neuralNet := Network{
+		numberOfTrainingNetworks: mySettings.numberOfTrainingNetworks,
+		task:                     mySettings.task,
+		loss:                     MeanSquaredError{},
+		ensembleSize:             1,
+		random:                   rand.New(rand.NewSource(mySettings.seed)),
+	}
+	if mySettings.task == multiLabel {
+		neuralNet.loss = BinaryCrossEntropy{}
+	}
+	neuralNet.network.InitializeNetwork(neuralNet.random, mySettings.nodesPerLayer, mySettings.outputLabels, activations, mySettings.initializers)
+
+	if mySettings.trainer != nil {
+		if err := neuralNet.useTrainer(*mySettings.trainer); err != nil {
+			return nil, err
+		}
+	}
+	if mySettings.loss != nil {
+		if err := neuralNet.SetLoss(mySettings.loss); err != nil {
+			return nil, err
+		}
+	}
+	return &neuralNet, nil
+}
+
+// checks if the settings can make a correct network
+func (mySettings *settings) validate() error {
+	if err := validateNetworkInit(mySettings.numberOfTrainingNetworks, mySettings.nodesPerLayer, mySettings.activations); err != nil {
+		return err
+	}
+	if err := validateInitializers(mySettings.nodesPerLayer, mySettings.initializers); err != nil {
+		return err
+	}
+	if mySettings.task == regression {
+		if mySettings.outputLabels != nil {
+			return errors.New("regression network can't have output labels")
+		}
+		return nil
+	}
+	return validateOutputLabels(mySettings.nodesPerLayer, mySettings.outputLabels)
+}
+
+// Sets the number of nodes of every layer, starting with the input one
+func WithLayers(nodesPerLayer ...int) Option {
+	return func(mySettings *settings) error {
+		mySettings.nodesPerLayer = nodesPerLayer
+		return nil
+	}
+}
+
+// Sets labels of the output nodes. There has to be one for every output node
+func WithLabels(outputLabels ...string) Option {
+	return func(mySettings *settings) error {
+		mySettings.outputLabels = outputLabels
+		return nil
+	}
+}
+
+// Sets the number of networks trained at once by the evolution algorithm
+func WithPopulationSize(numberOfTrainingNetworks int) Option {
+	return func(mySettings *settings) error {
+		if numberOfTrainingNetworks <= 0 {
+			return errors.New("number of training networks has to bigger than 0")
+		}
+		mySettings.numberOfTrainingNetworks = numberOfTrainingNetworks
+		return nil
+	}
+}
+
+// Sets activations of all layers except the input one, there has to be one for every such layer.
+// Otherwise every layer uses sigmoid, or the output layer is linear for regression
+func WithActivation(activations ...Activation) Option {
+	return func(mySettings *settings) error {
+		mySettings.activations = activations
+		return nil
+	}
+}
+
+// Sets initializers giving the initial weights and biases of all layers except the input one, there has to be one
+// for every such layer. Otherwise layers with the ReLU family use He and the rest use Xavier initialization
+func WithInitializer(initializers ...Initializer) Option {
+	return func(mySettings *settings) error {
+		mySettings.initializers = initializers
+		return nil
+	}
+}
+
+// Sets the seed of all random numbers used by the network, so the same seed gives
+// the same initial weights and the same training. Otherwise the current time is used
+func WithSeed(seed int64) Option {
+	return func(mySettings *settings) error {
+		mySettings.seed = seed
+		return nil
+	}
+}
+
+// Sets the trainer which continues its training in the next Train calls, e.g. the one returned by training.ResumeTrainer.
+// Its networks have to have the same structure as the created one. The trainer shouldn't be used elsewhere afterwards
+func WithTrainer(trainer training.Trainer) Option {
+	return func(mySettings *settings) error {
+		if !trainer.Initialized {
+			return errors.New("the trainer isn't initialized")
+		}
+		mySettings.trainer = &trainer
+		return nil
+	}
+}
+
+// Sets the loss used to measure the network's cost. The categorical cross entropy requires the softmax output layer
+func WithLoss(loss Loss) Option {
+	return func(mySettings *settings) error {
+		if loss == nil {
+			return errors.New("the loss can't be nil")
+		}
+		mySettings.loss = loss
+		return nil
+	}
+}
+
+// sets the task for which the network is created, used by the task specific constructors
+func withTask(myTask task) Option {
+	return func(mySettings *settings) error {
+		mySettings.task = myTask
+		return nil
+	}
+}
@@ -2,7 +2,7 @@ package NeuralNetwork
 
 import "errors"
 
-func (neuralNet *neuralNetwork) validateInputData(inputData []float64) error {
+func (neuralNet *Network) validateInputData(inputData []float64) error {
 	if len(inputData) != neuralNet.NumberOfInputNodes() {
 		return errors.New("number of input data has to be the same as number of input nodes")
 	}
@@ -14,7 +14,23 @@ func (neuralNet *neuralNetwork) validateInputData(inputData []float64) error {
 	return nil
 }
 
-func (neuralNet *neuralNetwork) validateTrainingInputData(inputs [][]float64, outputs []string) error {
+// checks all inputs of a batch, the network's structure is read only once
+func (neuralNet *Network) validateBatchInputData(inputs [][]float64) error {
+	numberOfInputNodes := neuralNet.NumberOfInputNodes()
+	for _, inputData := range inputs {
+		if len(inputData) != numberOfInputNodes {
+			return errors.New("number of input data has to be the same as number of input nodes")
+		}
+		for _, v := range inputData {
+			if v < 0 || v > 1 {
+				return errors.New("network input has to be between [0,1]")
+			}
+		}
+	}
+	return nil
+}
+
+func (neuralNet *Network) validateTrainingInputData(inputs [][]float64, outputs []string) error {
 	if len(inputs) != len(outputs) {
 		return errors.New("number of inputs slices is not the same as number of outputs")
 	}
@@ -23,24 +39,46 @@ func (neuralNet *neuralNetwork) validateTrainingInputData(inputs [][]float64, ou
 			return err
 		}
 	}
-	// checks if user given expected outputs were exist in trainer.outputLabels
 	for _, output := range outputs {
-		exists := false
-		for _, v := range neuralNet.network.GetOutputLabels() {
-			if v == output {
-				exists = true
-				break
-			}
+		if err := neuralNet.validateLabel(output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (neuralNet *Network) validateMultiLabelInputData(inputs [][]float64, outputs [][]string) error {
+	if len(inputs) != len(outputs) {
+		return errors.New("number of inputs slices is not the same as number of outputs")
+	}
+	for _, input := range inputs {
+		if err := neuralNet.validateInputData(input); err != nil {
+			return err
 		}
-		if !exists {
-			return errors.New("given output doesn't exist: " + output)
+	}
+	for _, labels := range outputs {
+		for _, label := range labels {
+			if err := neuralNet.validateLabel(label); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func validateNetworkInit(numberOfTrainingNetworks int, nodesPerLayer []int, outputLabels []string) error {
+// checks if user given expected output exists in network's output labels
+func (neuralNet *Network) validateLabel(output string) error {
+	for _, v := range neuralNet.network.GetOutputLabels() {
+		if v == output {
+			return nil
+		}
+	}
+	return errors.New("given output doesn't exist: " + output)
+}
+
+func validateNetworkInit(numberOfTrainingNetworks int, nodesPerLayer []int, activations []Activation) error {
 	if numberOfTrainingNetworks <= 0 {
 		return errors.New("number of training networks has to bigger than 0")
 	}
@@ -52,9 +90,83 @@ func validateNetworkInit(numberOfTrainingNetworks int, nodesPerLayer []int, outp
 			return errors.New("number of nodes per layer can't be lower than 1")
 		}
 	}
+	if len(activations) != 0 && len(activations) != len(nodesPerLayer)-1 {
+		return errors.New("number of activations has to be the same as number of layers without the input one")
+	}
+	for _, activation := range activations {
+		if !activation.IsValid() {
+			return errors.New("unknown activation function")
+		}
+	}
+
+	return nil
+}
+
+// checks if there is an initializer for every layer except the input one and none of them
+// nor the initializers wrapped by ZeroBias are nil
+func validateInitializers(nodesPerLayer []int, initializers []Initializer) error {
+	if len(initializers) != 0 && len(initializers) != len(nodesPerLayer)-1 {
+		return errors.New("number of initializers has to be the same as number of layers without the input one")
+	}
+	for _, initializer := range initializers {
+		for {
+			if initializer == nil {
+				return errors.New("the initializer can't be nil")
+			}
+			zeroBias, ok := initializer.(ZeroBias)
+			if !ok {
+				break
+			}
+			initializer = zeroBias.Initializer
+		}
+	}
+	return nil
+}
+
+func validateOutputLabels(nodesPerLayer []int, outputLabels []string) error {
 	if len(outputLabels) != nodesPerLayer[len(nodesPerLayer)-1] {
 		return errors.New("number of output labes has to be the same as number of output nodes")
 	}
+	return nil
+}
+
+func (neuralNet *Network) validateRegressionInputData(inputs [][]float64, targets [][]float64) error {
+	if len(inputs) != len(targets) {
+		return errors.New("number of inputs slices is not the same as number of targets")
+	}
+	for _, input := range inputs {
+		if err := neuralNet.validateInputData(input); err != nil {
+			return err
+		}
+	}
+	for _, target := range targets {
+		if len(target) != neuralNet.NumberOfOutputNodes() {
+			return errors.New("number of target values has to be the same as number of output nodes")
+		}
+	}
+	return nil
+}
+
+// checks if the network was created for one of the given tasks
+func (neuralNet *Network) validateTask(allowedTasks ...task) error {
+	for _, allowedTask := range allowedTasks {
+		if neuralNet.task == allowedTask {
+			return nil
+		}
+	}
+	return errors.New("it can't be done by a network created for " + neuralNet.task.String())
+}
 
+func (neuralNet *Network) validateLoss(loss Loss) error {
+	if loss == nil {
+		return errors.New("the loss can't be nil")
+	}
+	activations := neuralNet.network.GetActivations()
+	if _, ok := loss.(CategoricalCrossEntropy); ok && (len(activations) == 0 || activations[len(activations)-1] != Softmax) {
+		return errors.New("the categorical cross entropy requires the softmax output layer")
+	}
+	if huber, ok := loss.(Huber); ok && huber.Delta <= 0 {
+		return errors.New("huber's delta has to be bigger than 0")
+	}
 	return nil
 }
@@ -0,0 +1,159 @@
+package NeuralNetwork
+
+import (
+	"errors"
+
+	"github.com/Basileus1990/NeuralNetwork.git/integral/network"
+	"github.com/Basileus1990/NeuralNetwork.git/integral/training"
+)
+
+// Assigns the given data to the validation replacing the old validation data.
+// After every training iteration the best network is measured on it and the trained networks are ranked by it
+func (neuralNet *Network) LoadValidationData(inputs [][]float64, outputs []string) error {
+	if err := neuralNet.validateTask(classification); err != nil {
+		return err
+	}
+	if err := neuralNet.validateTrainingInputData(inputs, outputs); err != nil {
+		return err
+	}
+
+	validationData := make(network.DataSets, len(inputs))
+	for i := range validationData {
+		validationData[i].SetData(inputs[i], outputs[i])
+	}
+	neuralNet.validationData = validationData
+	return nil
+}
+
+// Assigns the given regression data to the validation replacing the old validation data
+func (neuralNet *Network) LoadRegressionValidationData(inputs [][]float64, targets [][]float64) error {
+	if err := neuralNet.validateTask(regression); err != nil {
+		return err
+	}
+	if err := neuralNet.validateRegressionInputData(inputs, targets); err != nil {
+		return err
+	}
+
+	validationData := make(network.DataSets, len(inputs))
+	for i := range validationData {
+		validationData[i].SetRegressionData(inputs[i], targets[i])
+	}
+	neuralNet.validationData = validationData
+	return nil
+}
+
+// Assigns the given multi-label data to the validation replacing the old validation data
+func (neuralNet *Network) LoadMultiLabelValidationData(inputs [][]float64, outputs [][]string) error {
+	if err := neuralNet.validateTask(multiLabel); err != nil {
+		return err
+	}
+	if err := neuralNet.validateMultiLabelInputData(inputs, outputs); err != nil {
+		return err
+	}
+
+	validationData := make(network.DataSets, len(inputs))
+	for i := range validationData {
+		validationData[i].SetMultiLabelData(inputs[i], outputs[i])
+	}
+	neuralNet.validationData = validationData
+	return nil
+}
+
+// Sets the fraction of the training data used for the validation if no validation data was loaded.
+// The data sets are chosen at random by the next training and stay held out of the training, also after resuming the trainer.
+// Then only newly loaded data sets are added to them, so a different fraction changes how many of them are held out.
+// 0 turns the split off
+func (neuralNet *Network) SetValidationSplit(fraction float64) error {
+	if fraction < 0 || fraction >= 1 {
+		return errors.New("validation split has to be between 0 and 1")
+	}
+	neuralNet.validationSplit = fraction
+	return nil
+}
+
+// Sets when the training is stopped because the validation cost doesn't decrease anymore.
+// It requires the validation data or the validation split
+func (neuralNet *Network) SetEarlyStopping(config training.EarlyStoppingConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	neuralNet.earlyStopping = &config
+	if neuralNet.trainer.Initialized {
+		return neuralNet.trainer.SetEarlyStopping(config)
+	}
+	return nil
+}
+
+// Returns the best network's validation cost and accuracy after every training iteration
+func (neuralNet *Network) GetValidationHistory() []training.ValidationResult {
+	return neuralNet.trainer.GetValidationHistory()
+}
+
+// Returns whether the last training was stopped early
+func (neuralNet *Network) StoppedEarly() bool {
+	return neuralNet.trainer.StoppedEarly()
+}
+
+// returns the data used for the training and the validation
+func (neuralNet *Network) splitTrainingData() (network.DataSets, network.DataSets, error) {
+	if len(neuralNet.validationData) != 0 || neuralNet.validationSplit == 0 {
+		return neuralNet.trainingData, neuralNet.validationData, nil
+	}
+	if len(neuralNet.heldOut) > len(neuralNet.trainingData) {
+		return nil, nil, errors.New("more data sets are held out for the validation than there is training data")
+	}
+	// the split is kept, so the trainer gets the same data and continues its epoch
+	if len(neuralNet.heldOut) == len(neuralNet.trainingData) && neuralNet.splitTraining != nil {
+		return neuralNet.splitTraining, neuralNet.splitValidation, nil
+	}
+
+	heldOut := neuralNet.holdOutNewData()
+	var trainingData, validationData network.DataSets
+	for i, isHeldOut := range heldOut {
+		if isHeldOut {
+			validationData = append(validationData, neuralNet.trainingData[i])
+		} else {
+			trainingData = append(trainingData, neuralNet.trainingData[i])
+		}
+	}
+	if len(trainingData) == 0 || len(validationData) == 0 {
+		return nil, nil, errors.New("the validation split leaves no training or validation data")
+	}
+	neuralNet.heldOut = heldOut
+	neuralNet.splitTraining, neuralNet.splitValidation = trainingData, validationData
+	return trainingData, validationData, nil
+}
+
+// Returns which training data sets are held out after holding out new ones chosen at random, so the validation data
+// keeps its fraction. The data can be ordered e.g. by labels, so the last ones can't be taken. The data sets held out
+// before stay held out, as otherwise the data the networks were measured on would get into the training
+func (neuralNet *Network) holdOutNewData() []bool {
+	numberOfHeldOut := 0
+	for _, isHeldOut := range neuralNet.heldOut {
+		if isHeldOut {
+			numberOfHeldOut++
+		}
+	}
+	numberOfNewData := len(neuralNet.trainingData) - len(neuralNet.heldOut)
+	numberToHoldOut := int(float64(len(neuralNet.trainingData))*neuralNet.validationSplit) - numberOfHeldOut
+	if numberToHoldOut < 0 {
+		numberToHoldOut = 0
+	} else if numberToHoldOut > numberOfNewData {
+		numberToHoldOut = numberOfNewData
+	}
+
+	heldOut := make([]bool, len(neuralNet.trainingData))
+	copy(heldOut, neuralNet.heldOut)
+	newHeldOut := heldOut[len(neuralNet.heldOut):]
+	for _, index := range neuralNet.random.Perm(numberOfNewData)[:numberToHoldOut] {
+		newHeldOut[index] = true
+	}
+	return heldOut
+}
+
+// replaces which training data sets are held out for the validation
+func (neuralNet *Network) setHeldOutData(heldOut []bool) {
+	neuralNet.heldOut = append([]bool(nil), heldOut...)
+	neuralNet.splitTraining, neuralNet.splitValidation = nil, nil
+}